@@ -4,15 +4,21 @@ import (
 	"context"
 	"github.com/robfig/cron/v3"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"shortlink-go/constant"
 	"shortlink-go/internal/handler"
 	"shortlink-go/internal/i18n"
 	"shortlink-go/internal/middleware"
 	"shortlink-go/internal/repository"
 	"shortlink-go/internal/service"
+	"shortlink-go/pkg/clientip"
 	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/proxyproto"
+	"shortlink-go/pkg/ratelimit"
+	"strings"
 	"syscall"
 	"time"
 
@@ -33,7 +39,75 @@ func initConfig() {
 	}
 }
 
-func startServer(r *gin.Engine) {
+// newClientIPResolver 根据 server.trusted_proxies 与 server.client_ip_headers 创建客户端 IP 解析器
+func newClientIPResolver() *clientip.Resolver {
+	resolver, err := clientip.New(
+		viper.GetStringSlice("server.trusted_proxies"),
+		viper.GetStringSlice("server.client_ip_headers"),
+	)
+	if err != nil {
+		logging.Logger.Fatal("Invalid trusted proxy configuration", zap.Error(err))
+	}
+	return resolver
+}
+
+// newRateLimiter 创建限流器：优先使用 Redis 滑动窗口，Redis 不可用时退回进程内计数
+func newRateLimiter() ratelimit.Limiter {
+	return &ratelimit.Fallback{
+		Primary: &ratelimit.Redis{Pool: repository.RedisPool, Prefix: constant.RateLimitPrefix},
+		Backup:  ratelimit.NewMemory(),
+		OnError: func(err error) {
+			logging.Logger.Warn("Redis rate limiter failed, using in-memory limiter", zap.Error(err))
+		},
+	}
+}
+
+// rateLimitExempt 解析 ratelimit.exempt_cidrs，返回判断客户端 IP 是否免于限流的函数
+func rateLimitExempt() func(ip net.IP) bool {
+	networks, err := clientip.ParseCIDRs(viper.GetStringSlice("ratelimit.exempt_cidrs"))
+	if err != nil {
+		logging.Logger.Fatal("Invalid rate limit exempt CIDR", zap.Error(err))
+	}
+	return func(ip net.IP) bool {
+		return clientip.ContainsIP(networks, ip)
+	}
+}
+
+// rateLimitRules 读取 ratelimit.groups.<group>.<维度> 的 limit 与 window，未配置或 limit 不大于 0 的维度不限流
+func rateLimitRules(group string, keys map[string]middleware.RateLimitKeyFunc) []middleware.RateLimitRule {
+	rules := make([]middleware.RateLimitRule, 0, len(keys))
+	for _, name := range []string{"ip", "code"} {
+		key, ok := keys[name]
+		if !ok {
+			continue
+		}
+		prefix := "ratelimit.groups." + group + "." + name
+		limit, window := viper.GetInt(prefix+".limit"), viper.GetDuration(prefix+".window")
+		if limit <= 0 || window <= 0 {
+			continue
+		}
+		rules = append(rules, middleware.RateLimitRule{Name: name, Limit: limit, Window: window, Key: key})
+	}
+	return rules
+}
+
+// clientIPKey 按客户端 IP 限流，key 中只保存 IP 的 HMAC；salt 不可用时不按 IP 限流
+func clientIPKey(c *gin.Context) string {
+	key, err := service.HashClientIP(c.ClientIP())
+	if err != nil {
+		logging.Logger.Error("Failed to hash rate limit key", zap.Error(err))
+		return ""
+	}
+	return key
+}
+
+// shortCodeKey 按访问的短码限流（预览页面与跳转共用计数），所有客户端共享同一计数
+// 用于限制单条短链的总访问速率，limit 应明显高于单个客户端的 ip 限额，避免单个客户端耗尽热门短链的额度
+func shortCodeKey(c *gin.Context) string {
+	return strings.TrimSuffix(strings.TrimPrefix(c.Request.URL.Path, "/"), "+")
+}
+
+func startServer(r *gin.Engine, resolver *clientip.Resolver) {
 	addr := viper.GetString("server.addr")
 	if addr == "" {
 		addr = ":8080"
@@ -44,10 +118,20 @@ func startServer(r *gin.Engine) {
 		Handler: r,
 	}
 
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		logging.Logger.Fatal("Failed to listen", zap.String("addr", addr), zap.Error(err))
+	}
+	// 负载均衡器通过 PROXY protocol 传递客户端地址时，只解析来自受信任代理的头部
+	if viper.GetBool("server.proxy_protocol") {
+		ln = proxyproto.NewListener(ln, resolver.IsTrusted, 5*time.Second)
+		logging.Logger.Info("PROXY protocol enabled")
+	}
+
 	// 启动服务器
 	go func() {
 		logging.Logger.Info("Server is running on " + addr)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			logging.Logger.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
@@ -89,6 +173,14 @@ func main() {
 	repository.InitDB(logging.Logger, logging.AtomicLevel)
 	repository.InitRedis()
 
+	// 内部网络配置与 ratelimit.exempt_cidrs 使用相同的解析规则，无法解析时拒绝启动
+	if err := service.InitInternalNetworks(); err != nil {
+		logging.Logger.Fatal("Invalid internal network CIDR", zap.Error(err))
+	}
+
+	// 为历史短链补全规范化目标地址的哈希
+	go service.BackfillCanonicalHashes()
+
 	// 初始化 i18n（加载 TOML 文件）
 	bundle, err := i18n.InitI18n([]string{
 		"./i18n/en.toml",
@@ -102,29 +194,89 @@ func main() {
 	r := gin.New()
 	r.Use(gin.Recovery()) // 显式添加 Recovery 中间件
 
+	// 客户端 IP 由 ClientIPMiddleware 按受信任代理配置解析，gin 自身不再信任任何转发请求头
+	if err := r.SetTrustedProxies(nil); err != nil {
+		panic(err)
+	}
+	clientIPResolver := newClientIPResolver()
+	r.Use(middleware.ClientIPMiddleware(clientIPResolver))
+
 	// 注册全局错误中间件
 	r.Use(middleware.GlobalErrorMiddleware())
-	r.Use(middleware.ZapGinLogger(logging.Logger))
+	// 开启 privacy.anonymize_logs 时日志中只记录截断后的网段
+	var anonymizeIP func(string) string
+	if service.AnonymizeLogs() {
+		anonymizeIP = service.TruncateIP
+	}
+	r.Use(middleware.ZapGinLogger(logging.Logger, anonymizeIP))
 	r.Use(middleware.CorsMiddleware())
 	// 使用 i18n 中间件
 	r.Use(middleware.I18nMiddleware(bundle))
 
+	rateLimitEnabled := viper.GetBool("ratelimit.enabled")
+	var limiter ratelimit.Limiter
+	var exempt func(ip net.IP) bool
+	if rateLimitEnabled {
+		limiter = newRateLimiter()
+		exempt = rateLimitExempt()
+	}
+
 	api := r.Group("/api")
+	if rateLimitEnabled {
+		api.Use(middleware.RateLimitMiddleware(limiter, "api", rateLimitRules("api", map[string]middleware.RateLimitKeyFunc{
+			"ip": clientIPKey,
+		}), exempt))
+	}
 	{
 		api.POST("/shortlink", handler.CreateShortLinkHandler)
+		api.POST("/shortlink/import", handler.ImportShortLinksHandler)
+		api.POST("/shortlink/preview", handler.PreviewTargetURLHandler)
 		api.GET("/shortlink", handler.ListShortLinksHandler)
+		api.GET("/shortlink/export", handler.ExportShortLinksHandler)
+		api.POST("/shortlink/bulk", handler.BulkShortLinksHandler)
+		api.GET("/shortlink/bulk/:jobId", handler.GetBulkJobHandler)
+		api.GET("/shortlink/trash", handler.ListTrashedShortLinksHandler)
+		api.GET("/shortlink/search", handler.SearchShortLinksHandler)
+		api.GET("/shortlink/:id", handler.GetShortLinkHandler)
 		api.PUT("/shortlink", handler.UpdateShortLinkHandler)
+		api.PATCH("/shortlink/:id", handler.PatchShortLinkHandler)
+		api.POST("/shortlink/:id/rename", handler.RenameShortCodeHandler)
+		api.GET("/shortlink/:id/stats", handler.GetShortLinkStatsHandler)
+		api.GET("/shortlink/:id/aliases", handler.ListShortLinkAliasesHandler)
+		api.POST("/shortlink/:id/aliases", handler.CreateShortLinkAliasHandler)
+		api.DELETE("/shortlink/:id/aliases/:aliasId", handler.DeleteShortLinkAliasHandler)
 		api.DELETE("/shortlink/:id", handler.DeleteShortLinkHandler)
+		api.POST("/shortlink/:id/restore", handler.RestoreShortLinkHandler)
+		api.GET("/shortlink/:id/revisions", handler.ListShortLinkRevisionsHandler)
+		api.POST("/shortlink/:id/revisions/:revision/rollback", handler.RollbackShortLinkHandler)
+
+		api.GET("/tag", handler.ListTagsHandler)
+		api.POST("/tag", handler.CreateTagHandler)
+		api.PATCH("/tag/:id", handler.UpdateTagHandler)
+		api.DELETE("/tag/:id", handler.DeleteTagHandler)
+		api.GET("/tag/:id/stats", handler.GetTagStatsHandler)
 
 		api.POST("/whitelist", handler.CreateWhitelistDomainHandler)
 		api.GET("/whitelist", handler.ListWhitelistDomainsHandler)
 		api.DELETE("/whitelist/:id", handler.DeleteWhitelistDomainHandler)
 	}
 
+	// 跳转与预览（GET/HEAD）按客户端 IP 与短码限流，防止遍历短码；其他请求不计入 redirect 组
+	var allowRedirect func(c *gin.Context) bool
+	if rateLimitEnabled {
+		allowRedirect = middleware.RateLimitCheck(limiter, "redirect", rateLimitRules("redirect", map[string]middleware.RateLimitKeyFunc{
+			"ip":   clientIPKey,
+			"code": shortCodeKey,
+		}), exempt)
+	}
+
 	// 使用中间件调用 RedirectToTargetURLHandler（避免与 /handler 冲突）
 	r.Use(func(c *gin.Context) {
-		if c.Request.Method != http.MethodGet {
-			c.Next() // 只处理 GET 请求
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next() // 只处理 GET/HEAD 请求（HEAD 计入机器流量）
+			return
+		}
+		if allowRedirect != nil && !allowRedirect(c) {
 			return
 		}
 		// 调用处理函数（所有逻辑集中在此）
@@ -144,7 +296,16 @@ func main() {
 		logging.Logger.Fatal("Failed to schedule cron job", zap.Error(addErr))
 	}
 
+	// 每天凌晨 3 点彻底删除超过保留期的回收站短链
+	if _, err := c.AddFunc("0 3 * * *", func() {
+		if err := service.PurgeExpiredTrash(); err != nil {
+			logging.Logger.Error("Failed to purge short link trash", zap.Error(err))
+		}
+	}); err != nil {
+		logging.Logger.Fatal("Failed to schedule trash purge job", zap.Error(err))
+	}
+
 	c.Start()
 
-	startServer(r)
+	startServer(r, clientIPResolver)
 }
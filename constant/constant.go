@@ -1,3 +1,6 @@
 package constant
 
 const LanguageContextKey = "language"
+
+// TrustedProxyContextKey gin 上下文中记录直接连接方是否为受信任代理的键，只有受信任代理写入的转发请求头才可采用
+const TrustedProxyContextKey = "trustedProxy"
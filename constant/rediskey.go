@@ -9,15 +9,25 @@ import (
 const (
 	BasePrefix = "redirect:"
 	Separator  = ":"
+
+	DailyKeyTTLDays = 3 // 每日 PV/UV key 的保留天数
+
+	RateLimitPrefix = BasePrefix + "ratelimit" + Separator // 限流计数 key 前缀：redirect:ratelimit:<group>:<rule>:<value>
 )
 
 // Redis 键模板
 const (
-	ShortCode = BasePrefix + "shortcode:%s"
-	DailyPV   = BasePrefix + "pv" + Separator + "%s"                    // redirect:pv:yyyyMMdd
-	DailyUV   = BasePrefix + "uv" + Separator + "%s" + Separator + "%s" // redirect:uv:yyyyMMdd:shortcode
-	TotalPV   = BasePrefix + "total_pv" + Separator + "%s"              // redirect:total_pv:shortcode
-	TotalUV   = BasePrefix + "total_uv" + Separator + "%s"              // redirect:total_uv:shortcode
+	ShortCode   = BasePrefix + "shortcode:%s"
+	DailyPV     = BasePrefix + "pv" + Separator + "%s"                    // redirect:pv:yyyyMMdd
+	DailyUV     = BasePrefix + "uv" + Separator + "%s" + Separator + "%s" // redirect:uv:yyyyMMdd:shortcode
+	TotalPV     = BasePrefix + "total_pv" + Separator + "%s"              // redirect:total_pv:shortcode
+	TotalUV     = BasePrefix + "total_uv" + Separator + "%s"              // redirect:total_uv:shortcode
+	AliasPV     = BasePrefix + "alias_pv" + Separator + "%s"              // redirect:alias_pv:alias
+	AliasUV     = BasePrefix + "alias_uv" + Separator + "%s"              // redirect:alias_uv:alias
+	BotPV       = BasePrefix + "bot_pv" + Separator + "%s"                // redirect:bot_pv:shortcode
+	InternalPV  = BasePrefix + "internal_pv" + Separator + "%s"           // redirect:internal_pv:shortcode
+	OptOutPV    = BasePrefix + "opt_out_pv" + Separator + "%s"            // redirect:opt_out_pv:shortcode
+	VisitorSalt = BasePrefix + "visitor_salt" + Separator + "%s"          // redirect:visitor_salt:yyyyMMdd
 )
 
 // GetShortCodeKey 生成 shortCode key
@@ -30,6 +40,16 @@ func GetDateKey() string {
 	return time.Now().Format("20060102") // Go 中日期格式规则：2006-01-02
 }
 
+// GetRecentDateKeys 生成最近 days 天（含今天）的日期键，按从新到旧排序
+func GetRecentDateKeys(days int) []string {
+	now := time.Now()
+	keys := make([]string, 0, days)
+	for i := 0; i < days; i++ {
+		keys = append(keys, now.AddDate(0, 0, -i).Format("20060102"))
+	}
+	return keys
+}
+
 // GetDailyPVKey 生成每日 PV 键（格式：redirect:pv:yyyyMMdd）
 func GetDailyPVKey(date string) string {
 	return fmt.Sprintf(DailyPV, date)
@@ -49,3 +69,33 @@ func GetTotalUVKey(shortcode string) string {
 func GetTotalPVKey(shortcode string) string {
 	return fmt.Sprintf(TotalPV, shortcode)
 }
+
+// GetAliasPVKey 生成别名总 PV 键（格式：redirect:alias_pv:alias）
+func GetAliasPVKey(alias string) string {
+	return fmt.Sprintf(AliasPV, alias)
+}
+
+// GetAliasUVKey 生成别名总 UV 键（格式：redirect:alias_uv:alias）
+func GetAliasUVKey(alias string) string {
+	return fmt.Sprintf(AliasUV, alias)
+}
+
+// GetBotPVKey 生成机器流量总 PV 键（格式：redirect:bot_pv:shortcode）
+func GetBotPVKey(shortcode string) string {
+	return fmt.Sprintf(BotPV, shortcode)
+}
+
+// GetInternalPVKey 生成内部网络访问总 PV 键（格式：redirect:internal_pv:shortcode）
+func GetInternalPVKey(shortcode string) string {
+	return fmt.Sprintf(InternalPV, shortcode)
+}
+
+// GetVisitorSaltKey 生成访客标识每日 salt 的键（格式：redirect:visitor_salt:yyyyMMdd）
+func GetVisitorSaltKey(date string) string {
+	return fmt.Sprintf(VisitorSalt, date)
+}
+
+// GetOptOutPVKey 生成拒绝跟踪访问总 PV 键（格式：redirect:opt_out_pv:shortcode）
+func GetOptOutPVKey(shortcode string) string {
+	return fmt.Sprintf(OptOutPV, shortcode)
+}
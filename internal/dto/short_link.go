@@ -1,24 +1,116 @@
 package dto
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"net/url"
+	"shortlink-go/internal/model"
+	"shortlink-go/pkg/clientip"
 	"shortlink-go/pkg/utils"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // CreateShortLinkRequest 用于创建短链的请求参数
 type CreateShortLinkRequest struct {
-	TargetURL    string `json:"targetUrl" binding:"required,url"` // Gin 内置 URL 校验
-	ShortCode    string `json:"shortCode" binding:"required,max=32"`
-	RedirectCode int    `json:"redirectCode" binding:"required,oneof=301 302 307"` // 仅允许301/302/307
-	Disabled     bool   `json:"disabled" `
+	TargetURL    string          `json:"targetUrl" binding:"required,url"` // Gin 内置 URL 校验
+	ShortCode    string          `json:"shortCode" binding:"required,max=32"`
+	RedirectCode int             `json:"redirectCode" binding:"required,oneof=301 302 307 308"` // 仅允许301/302/307/308
+	RedirectMode string          `json:"redirectMode"`                                          // 为空时默认 http
+	Disabled     bool            `json:"disabled" `
+	ExpiresAt    *time.Time      `json:"expiresAt"`
+	Tags         []string        `json:"tags"`
+	Description  string          `json:"description"`
+	Notes        string          `json:"notes"`
+	UTM          model.UTMParams `json:"utm"`
+	OpenGraph    model.OpenGraph `json:"openGraph"`
+
+	QueryPassthrough bool `json:"queryPassthrough"` // 将访问时的查询参数追加到目标地址
+	PathPassthrough  bool `json:"pathPassthrough"`  // 前缀匹配，短码之后的路径追加到目标地址
+
+	// InternalCIDRs 本短链额外视为内部网络的 CIDR 或 IP（如合作方的测试网络），访问只计入 internalPv
+	InternalCIDRs []string `json:"internalCidrs"`
+
+	// ReuseExisting 已存在规范化目标相同、跳转设置一致的可用短链时直接返回该短链，不再创建
+	ReuseExisting bool `json:"reuseExisting"`
+}
+
+// DuplicateShortLink 规范化后目标地址相同的已有短链
+type DuplicateShortLink struct {
+	ID        uint   `json:"id"`
+	ShortCode string `json:"shortCode"`
+	TargetURL string `json:"targetUrl"`
+}
+
+// CreateShortLinkResponse 创建短链的结果
+type CreateShortLinkResponse struct {
+	ShortLink  *model.ShortLink     `json:"shortLink"`
+	Reused     bool                 `json:"reused"`     // 为 true 时返回的是已有短链，未创建新短链
+	Duplicates []DuplicateShortLink `json:"duplicates"` // 目标相同的其他短链（仅作提示）
 }
 
 // UpdateShortLinkRequest 用于更新短链的请求参数
 type UpdateShortLinkRequest struct {
 	ID           uint   `json:"id"`
 	TargetURL    string `json:"targetUrl" binding:"required,url" msg:"targetUrl must be a valid URL"` // 必填字段，Gin 内置 URL 校验
-	RedirectCode int    `json:"redirectCode" binding:"required,oneof=301 302 307"`                    // 仅允许301/302/307
+	RedirectCode int    `json:"redirectCode" binding:"required,oneof=301 302 307 308"`                // 仅允许301/302/307/308
 	Disabled     *bool  `json:"disabled" `
+	Version      *uint  `json:"version"` // 乐观锁版本号，未携带 If-Match 请求头时必填
+}
+
+// RenameShortCodeRequest 用于修改短码的请求参数
+type RenameShortCodeRequest struct {
+	NewShortCode string `json:"newShortCode" binding:"required,max=32"`
+	KeepAlias    bool   `json:"keepAlias"` // 保留旧短码作为别名，旧链接继续可用
+	Version      *uint  `json:"version"`   // 乐观锁版本号，未携带 If-Match 请求头时必填
+}
+
+// RollbackShortLinkRequest 用于回滚短链的请求参数（请求体可省略，此时必须携带 If-Match 请求头）
+type RollbackShortLinkRequest struct {
+	Version *uint `json:"version"` // 乐观锁版本号，未携带 If-Match 请求头时必填
+}
+
+// CreateShortLinkAliasRequest 用于添加短链别名的请求参数
+type CreateShortLinkAliasRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// PatchField JSON Merge Patch 字段：区分“未传”、“显式 null”与具体值
+type PatchField[T any] struct {
+	Set   bool // 请求体中出现了该字段
+	Null  bool // 字段值为 null（表示清空/恢复默认值）
+	Value T
+}
+
+// UnmarshalJSON 只有字段出现在请求体中时才会被调用
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// PatchShortLinkRequest 用于部分更新短链的请求参数（JSON Merge Patch，RFC 7396）
+type PatchShortLinkRequest struct {
+	TargetURL        PatchField[string]          `json:"targetUrl"`        // 不允许为 null
+	RedirectCode     PatchField[int]             `json:"redirectCode"`     // null 恢复默认 302
+	RedirectMode     PatchField[string]          `json:"redirectMode"`     // null 恢复默认 http
+	Disabled         PatchField[bool]            `json:"disabled"`         // null 视为 false
+	ExpiresAt        PatchField[time.Time]       `json:"expiresAt"`        // null 表示永不过期
+	Tags             PatchField[[]string]        `json:"tags"`             // 整体替换，null 清空
+	Description      PatchField[string]          `json:"description"`      // null 清空
+	Notes            PatchField[string]          `json:"notes"`            // null 清空
+	UTM              PatchField[model.UTMParams] `json:"utm"`              // 整体替换，null 清空
+	OpenGraph        PatchField[model.OpenGraph] `json:"openGraph"`        // 整体替换，null 清空
+	QueryPassthrough PatchField[bool]            `json:"queryPassthrough"` // null 视为 false
+	PathPassthrough  PatchField[bool]            `json:"pathPassthrough"`  // null 视为 false
+	InternalCIDRs    PatchField[[]string]        `json:"internalCidrs"`    // 整体替换，null 清空
+	Version          PatchField[uint]            `json:"version"`          // 乐观锁版本号，未携带 If-Match 请求头时必填
 }
 
 // Validate 自定义验证逻辑
@@ -39,5 +131,263 @@ func (r *CreateShortLinkRequest) Validate() error {
 		}
 	}
 
+	// 3. 可选字段校验（标签先去掉两端空白，与写入时保持一致）
+	r.Tags = trimTagNames(r.Tags)
+	if err := validateOptionalFields(r.ExpiresAt, r.Tags, r.Description, r.Notes); err != nil {
+		return gin.Error{
+			Err:  err,
+			Type: gin.ErrorTypeBind,
+		}
+	}
+
+	if err := ValidateUTM(r.UTM); err != nil {
+		return gin.Error{
+			Err:  err,
+			Type: gin.ErrorTypeBind,
+		}
+	}
+
+	if err := ValidateOpenGraph(r.OpenGraph); err != nil {
+		return gin.Error{
+			Err:  err,
+			Type: gin.ErrorTypeBind,
+		}
+	}
+
+	if r.RedirectMode != "" {
+		if err := ValidateRedirectMode(r.RedirectMode); err != nil {
+			return gin.Error{
+				Err:  err,
+				Type: gin.ErrorTypeBind,
+			}
+		}
+	}
+
+	if err := ValidateInternalCIDRs(r.InternalCIDRs); err != nil {
+		return gin.Error{
+			Err:  err,
+			Type: gin.ErrorTypeBind,
+		}
+	}
+
 	return nil
 }
+
+// Validate 逐字段校验请求体中出现的字段
+func (r *PatchShortLinkRequest) Validate() error {
+	if r.TargetURL.Set {
+		if r.TargetURL.Null {
+			return gin.Error{Err: fmt.Errorf("error.target_url_required"), Type: gin.ErrorTypeBind}
+		}
+		if err := utils.ValidateTargetURL(r.TargetURL.Value); err != nil {
+			return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+		}
+	}
+
+	if r.RedirectCode.Set && !r.RedirectCode.Null {
+		if err := utils.ValidateRedirectCode(r.RedirectCode.Value); err != nil {
+			return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+		}
+	}
+
+	if r.RedirectMode.Set && !r.RedirectMode.Null {
+		if err := ValidateRedirectMode(r.RedirectMode.Value); err != nil {
+			return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+		}
+	}
+
+	var expiresAt *time.Time
+	if r.ExpiresAt.Set && !r.ExpiresAt.Null {
+		expiresAt = &r.ExpiresAt.Value
+	}
+	r.Tags.Value = trimTagNames(r.Tags.Value)
+	if err := validateOptionalFields(expiresAt, r.Tags.Value, r.Description.Value, r.Notes.Value); err != nil {
+		return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+	}
+
+	if err := ValidateUTM(r.UTM.Value); err != nil {
+		return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+	}
+
+	if err := ValidateOpenGraph(r.OpenGraph.Value); err != nil {
+		return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+	}
+
+	if err := ValidateInternalCIDRs(r.InternalCIDRs.Value); err != nil {
+		return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+	}
+
+	return nil
+}
+
+// validateOptionalFields 校验过期时间、标签、说明与备注
+func validateOptionalFields(expiresAt *time.Time, tags []string, description, notes string) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return fmt.Errorf("error.expires_at_invalid")
+	}
+
+	for _, tag := range tags {
+		if err := utils.ValidateTagName(tag); err != nil {
+			return err
+		}
+	}
+
+	if err := utils.ValidateDescription(description); err != nil {
+		return err
+	}
+	return utils.ValidateNotes(notes)
+}
+
+// trimTagNames 返回去掉两端空白后的标签名（新切片，不修改调用方的数据）
+func trimTagNames(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	trimmed := make([]string, len(tags))
+	for i, tag := range tags {
+		trimmed[i] = strings.TrimSpace(tag)
+	}
+	return trimmed
+}
+
+// ValidateUTM 校验 UTM 参数长度
+func ValidateUTM(utm model.UTMParams) error {
+	for _, value := range []string{utm.Source, utm.Medium, utm.Campaign, utm.Term, utm.Content} {
+		if utf8.RuneCountInString(value) > 128 {
+			return fmt.Errorf("error.utm_too_long")
+		}
+	}
+	return nil
+}
+
+// ValidateOpenGraph 校验社交平台预览信息，图片必须是 http/https 地址
+func ValidateOpenGraph(og model.OpenGraph) error {
+	if utf8.RuneCountInString(og.Title) > 256 || utf8.RuneCountInString(og.Description) > 512 {
+		return fmt.Errorf("error.open_graph_too_long")
+	}
+	if og.Image == "" {
+		return nil
+	}
+	if len(og.Image) > 2048 {
+		return fmt.Errorf("error.open_graph_too_long")
+	}
+	if u, err := url.ParseRequestURI(og.Image); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("error.open_graph_image_invalid")
+	}
+	return nil
+}
+
+// maxInternalCIDRs 单条短链最多配置的内部网络条目数
+const maxInternalCIDRs = 32
+
+// ValidateInternalCIDRs 校验短链的内部网络列表，解析规则与 stats.internal_cidrs 相同，不允许空条目
+func ValidateInternalCIDRs(entries []string) error {
+	if len(entries) > maxInternalCIDRs || len(strings.Join(entries, ",")) > 1024 {
+		return fmt.Errorf("error.internal_cidrs_too_many")
+	}
+	for _, entry := range entries {
+		if network, err := clientip.ParseCIDR(entry); err != nil || network == nil {
+			return fmt.Errorf("error.internal_cidr_invalid")
+		}
+	}
+	return nil
+}
+
+// ValidateRedirectMode 校验跳转方式是否受支持
+func ValidateRedirectMode(mode string) error {
+	switch mode {
+	case model.RedirectModeHTTP, model.RedirectModeMeta, model.RedirectModeJS, model.RedirectModeFrame:
+		return nil
+	default:
+		return fmt.Errorf("error.redirect_mode_invalid")
+	}
+}
+
+// PreviewTargetURLRequest 预览合并 UTM 参数后的跳转地址
+type PreviewTargetURLRequest struct {
+	TargetURL string          `json:"targetUrl" binding:"required"`
+	UTM       model.UTMParams `json:"utm"`
+}
+
+// ShortLinkQuery 短链列表的筛选与排序条件（列表、导出、批量操作共用）
+type ShortLinkQuery struct {
+	ShortCode      string     `json:"shortCode"`
+	ShortCodeMatch string     `json:"shortCodeMatch"` // exact / prefix / contains（默认）
+	TargetURL      string     `json:"targetUrl"`      // 模糊匹配
+	RedirectCode   int        `json:"redirectCode"`   // 0 表示不限
+	Disabled       *bool      `json:"disabled"`       // nil 表示不限
+	Tags           []string   `json:"tags"`           // 按标签名筛选
+	TagMode        string     `json:"tagMode"`        // and：包含全部标签；or（默认）：包含任一标签
+	CreatedFrom    *time.Time `json:"createdFrom"`
+	CreatedTo      *time.Time `json:"createdTo"`
+	UpdatedFrom    *time.Time `json:"updatedFrom"`
+	UpdatedTo      *time.Time `json:"updatedTo"`
+	SortBy         string     `json:"sortBy"`    // id（默认）/ createdAt / updatedAt / totalPv / totalUv
+	SortOrder      string     `json:"sortOrder"` // asc / desc（默认）
+}
+
+// 标签筛选模式
+const (
+	TagModeAnd = "and"
+	TagModeOr  = "or"
+)
+
+// 短码匹配方式
+const (
+	ShortCodeMatchExact    = "exact"
+	ShortCodeMatchPrefix   = "prefix"
+	ShortCodeMatchContains = "contains"
+)
+
+// 排序方向
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Validate 校验筛选条件中的枚举值，返回 i18n key
+func (q *ShortLinkQuery) Validate() error {
+	switch q.ShortCodeMatch {
+	case "", ShortCodeMatchExact, ShortCodeMatchPrefix, ShortCodeMatchContains:
+	default:
+		return fmt.Errorf("error.shortcode_match_invalid")
+	}
+
+	switch q.TagMode {
+	case "", TagModeAnd, TagModeOr:
+	default:
+		return fmt.Errorf("error.tag_mode_invalid")
+	}
+
+	switch q.SortBy {
+	case "", "id", "createdAt", "updatedAt", "totalPv", "totalUv":
+	default:
+		return fmt.Errorf("error.sort_invalid")
+	}
+
+	switch q.SortOrder {
+	case "", SortOrderAsc, SortOrderDesc:
+	default:
+		return fmt.Errorf("error.sort_invalid")
+	}
+	return nil
+}
+
+// HasCriteria 判断是否包含至少一个筛选条件；排序与匹配方式不算筛选条件
+func (q *ShortLinkQuery) HasCriteria() bool {
+	return q.ShortCode != "" ||
+		q.TargetURL != "" ||
+		q.RedirectCode != 0 ||
+		q.Disabled != nil ||
+		len(q.Tags) > 0 ||
+		q.CreatedFrom != nil ||
+		q.CreatedTo != nil ||
+		q.UpdatedFrom != nil ||
+		q.UpdatedTo != nil
+}
+
+// TrashedShortLink 回收站中的短链，PurgeAt 之后将被彻底删除
+type TrashedShortLink struct {
+	model.ShortLink
+	PurgeAt time.Time `json:"purgeAt"`
+}
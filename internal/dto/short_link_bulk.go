@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// 批量操作类型
+const (
+	BulkActionDisable  = "disable"
+	BulkActionEnable   = "enable"
+	BulkActionDelete   = "delete"
+	BulkActionTag      = "tag"      // 追加标签
+	BulkActionRetarget = "retarget" // 修改目标地址
+)
+
+// 批量任务状态
+const (
+	BulkJobPending   = "pending"
+	BulkJobRunning   = "running"
+	BulkJobCompleted = "completed"
+)
+
+// BulkShortLinkRequest 批量操作请求：ids 与 filter 二选一
+type BulkShortLinkRequest struct {
+	IDs          []uint          `json:"ids"`
+	Filter       *ShortLinkQuery `json:"filter"` // 与列表接口相同的筛选条件
+	Action       string          `json:"action" binding:"required,oneof=disable enable delete tag retarget"`
+	Tags         []string        `json:"tags"`         // action=tag 时追加的标签
+	TargetURL    string          `json:"targetUrl"`    // action=retarget 时的新目标地址
+	RedirectCode int             `json:"redirectCode"` // action=retarget 时可选，0 表示不修改
+}
+
+// BulkJobError 单条短链处理失败的原因
+type BulkJobError struct {
+	ID      uint   `json:"id"`
+	Message string `json:"message"`
+}
+
+// BulkJobStatus 批量任务进度
+type BulkJobStatus struct {
+	ID         string         `json:"id"`
+	Action     string         `json:"action"`
+	Status     string         `json:"status"`
+	Total      int            `json:"total"`
+	Processed  int            `json:"processed"`
+	Succeeded  int            `json:"succeeded"`
+	Failed     int            `json:"failed"`
+	Errors     []BulkJobError `json:"errors"` // 最多保留前 100 条
+	CreatedAt  time.Time      `json:"createdAt"`
+	FinishedAt *time.Time     `json:"finishedAt"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// 导入时短码冲突的处理策略
+const (
+	ImportConflictSkip      = "skip"      // 跳过已存在的短码
+	ImportConflictOverwrite = "overwrite" // 覆盖已存在短链的配置
+	ImportConflictFail      = "fail"      // 存在任一冲突或校验失败的行时整体失败，不写入任何数据
+)
+
+// 导入行的处理结果
+const (
+	ImportStatusCreated  = "created"
+	ImportStatusUpdated  = "updated"
+	ImportStatusSkipped  = "skipped"
+	ImportStatusInvalid  = "invalid"
+	ImportStatusConflict = "conflict"
+	ImportStatusFailed   = "failed"
+)
+
+// ImportShortLinkRow 导入文件中的一行（NDJSON 每行即为该结构的 JSON）
+type ImportShortLinkRow struct {
+	ShortCode    string     `json:"shortCode"`
+	TargetURL    string     `json:"targetUrl"`
+	RedirectCode int        `json:"redirectCode"` // 为空时默认 302
+	Disabled     bool       `json:"disabled"`
+	ExpiresAt    *time.Time `json:"expiresAt"`
+	Tags         []string   `json:"tags"` // 为 nil 表示未提供，覆盖时保留原有标签；NDJSON 中的 [] 表示清空标签
+}
+
+// ImportRowResult 单行导入结果
+type ImportRowResult struct {
+	Row       int    `json:"row"` // 从 1 开始的数据行号（不含 CSV 表头）
+	ShortCode string `json:"shortCode"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ImportReport 导入报告
+type ImportReport struct {
+	DryRun     bool              `json:"dryRun"`
+	OnConflict string            `json:"onConflict"`
+	Aborted    bool              `json:"aborted"` // onConflict=fail 且存在冲突或校验失败的行时为 true，未写入任何数据
+	Total      int               `json:"total"`
+	Created    int               `json:"created"`
+	Updated    int               `json:"updated"`
+	Skipped    int               `json:"skipped"`
+	Failed     int               `json:"failed"` // invalid + conflict + failed
+	Rows       []ImportRowResult `json:"rows"`
+}
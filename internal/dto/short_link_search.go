@@ -0,0 +1,12 @@
+package dto
+
+import "shortlink-go/internal/model"
+
+// ShortLinkSearchResult 全文检索结果
+// Highlights 的 key 为字段名（shortCode / targetUrl / description / notes / tags），
+// 值为命中片段：匹配部分以 <mark></mark> 包裹，其余内容已做 HTML 转义
+type ShortLinkSearchResult struct {
+	model.ShortLink
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights"`
+}
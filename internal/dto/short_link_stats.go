@@ -0,0 +1,30 @@
+package dto
+
+// DailyStatItem 单日统计
+type DailyStatItem struct {
+	Date string `json:"date"`
+	PV   uint64 `json:"pv"`
+	UV   uint64 `json:"uv"`
+}
+
+// AliasStatItem 单个别名的统计
+type AliasStatItem struct {
+	Code    string `json:"code"`
+	TotalPV uint64 `json:"totalPv"`
+	TotalUV uint64 `json:"totalUv"` // 含义同 ShortLinkStatsResponse.TotalUV
+}
+
+// ShortLinkStatsResponse 短链统计报表（别名的访问已汇总到主短链，同时按别名单独列出）
+type ShortLinkStatsResponse struct {
+	ID         uint            `json:"id"`
+	ShortCode  string          `json:"shortCode"`
+	TotalPV    uint64          `json:"totalPv"`    // 外部访问
+	TotalUV    uint64          `json:"totalUv"`    // privacy.visitor_id 为 hash 时访客标识每天变化，跨天重复访问会重复计数
+	BotPV      uint64          `json:"botPv"`      // 机器流量，未计入 TotalPV/TotalUV
+	InternalPV uint64          `json:"internalPv"` // 内部网络访问，未计入 TotalPV/TotalUV
+	OptOutPV   uint64          `json:"optOutPv"`   // 携带 DNT/GPC 拒绝跟踪的访问，已计入 TotalPV，未计入 TotalUV
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	Daily      []DailyStatItem `json:"daily"`
+	Aliases    []AliasStatItem `json:"aliases"`
+}
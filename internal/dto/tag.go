@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"github.com/gin-gonic/gin"
+	"shortlink-go/internal/model"
+	"shortlink-go/pkg/utils"
+	"strings"
+)
+
+// CreateTagRequest 用于创建标签的请求参数
+type CreateTagRequest struct {
+	Name        string `json:"name" binding:"required,max=64"`
+	Description string `json:"description"`
+}
+
+// Validate 自定义验证逻辑
+func (r *CreateTagRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if err := utils.ValidateTagName(r.Name); err != nil {
+		return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+	}
+	if err := utils.ValidateDescription(r.Description); err != nil {
+		return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+	}
+	return nil
+}
+
+// UpdateTagRequest 用于修改标签的请求参数，未传的字段保持不变
+type UpdateTagRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// Validate 自定义验证逻辑
+func (r *UpdateTagRequest) Validate() error {
+	if r.Name != nil {
+		name := strings.TrimSpace(*r.Name)
+		if err := utils.ValidateTagName(name); err != nil {
+			return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+		}
+		r.Name = &name
+	}
+	if r.Description != nil {
+		if err := utils.ValidateDescription(*r.Description); err != nil {
+			return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+		}
+	}
+	return nil
+}
+
+// TagItem 标签列表项（附带使用该标签的短链数量，不含回收站）
+type TagItem struct {
+	model.Tag
+	LinkCount int64 `json:"linkCount"`
+}
+
+// TagStatsResponse 标签维度的汇总统计（UV 为各短链 UV 之和，跨短链的同一访客会重复计数）
+type TagStatsResponse struct {
+	ID        uint            `json:"id"`
+	Name      string          `json:"name"`
+	LinkCount int64           `json:"linkCount"`
+	TotalPV   uint64          `json:"totalPv"`
+	TotalUV   uint64          `json:"totalUv"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Daily     []DailyStatItem `json:"daily"`
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/service"
+	"shortlink-go/response"
+	"strconv"
+)
+
+// ListShortLinkAliasesHandler 查询短链别名（GET /api/shortlink/:id/aliases）
+func ListShortLinkAliasesHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	aliases, err := service.ListShortLinkAliases(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(aliases, "success"))
+}
+
+// CreateShortLinkAliasHandler 添加短链别名（POST /api/shortlink/:id/aliases）
+func CreateShortLinkAliasHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	var req dto.CreateShortLinkAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	alias, err := service.CreateShortLinkAlias(c.Request.Context(), uint(id), req.Code)
+	if err != nil {
+		zap.L().Warn("Short link alias creation failed",
+			zap.Error(err),
+			zap.Uint("id", uint(id)),
+			zap.String("alias", req.Code),
+		)
+		_ = c.Error(err)
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.alias_created", nil)
+	c.JSON(http.StatusOK, response.OK(alias, message))
+}
+
+// DeleteShortLinkAliasHandler 删除短链别名（DELETE /api/shortlink/:id/aliases/:aliasId）
+func DeleteShortLinkAliasHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	aliasID, err := strconv.ParseUint(c.Param("aliasId"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	if err := service.DeleteShortLinkAlias(c.Request.Context(), uint(id), uint(aliasID)); err != nil {
+		zap.L().Warn("Short link alias deletion failed",
+			zap.Error(err),
+			zap.Uint("id", uint(id)),
+			zap.Uint("alias_id", uint(aliasID)),
+		)
+		_ = c.Error(err)
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.alias_deleted", nil)
+	c.JSON(http.StatusOK, response.OK("", message))
+}
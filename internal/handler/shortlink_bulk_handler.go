@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/service"
+	"shortlink-go/response"
+)
+
+// BulkShortLinksHandler 批量操作短链（POST /api/shortlink/bulk），任务在后台执行，返回任务 ID 供查询进度
+func BulkShortLinksHandler(c *gin.Context) {
+	var req dto.BulkShortLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	job, err := service.StartBulkShortLinkJob(c.Request.Context(), req)
+	if err != nil {
+		zap.L().Warn("Bulk short link job failed to start",
+			zap.Error(err),
+			zap.String("action", req.Action),
+		)
+		_ = c.Error(err)
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.bulk_job_started", nil)
+	c.JSON(http.StatusAccepted, response.OK(job, message))
+}
+
+// GetBulkJobHandler 查询批量任务进度（GET /api/shortlink/bulk/:jobId）
+func GetBulkJobHandler(c *gin.Context) {
+	job, err := service.GetBulkJobStatus(c.Request.Context(), c.Param("jobId"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(job, "success"))
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"shortlink-go/internal/service"
+	"strings"
+	"time"
+)
+
+// ExportShortLinksHandler 流式导出短链（GET /api/shortlink/export?format=csv|ndjson|xlsx&statsFrom=&statsTo=）
+// 筛选参数与 ListShortLinksHandler 相同
+func ExportShortLinksHandler(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", service.ExportFormatCSV))
+
+	contentType, err := service.ValidateExportFormat(c.Request.Context(), format)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	query, err := parseShortLinkQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filename := fmt.Sprintf("shortlinks-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	if err := service.ExportShortLinks(c.Request.Context(), c.Writer, format, query, c.Query("statsFrom"), c.Query("statsTo")); err != nil {
+		zap.L().Warn("Short link export failed",
+			zap.Error(err),
+			zap.String("format", format),
+			zap.Bool("written", c.Writer.Written()),
+		)
+		// 尚未写出任何数据时仍可返回 JSON 错误，否则只能中断输出
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			_ = c.Error(err)
+		}
+		return
+	}
+}
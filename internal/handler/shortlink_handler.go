@@ -1,17 +1,25 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"net/http"
+	"shortlink-go/constant"
 	"shortlink-go/internal/apperrors"
 	"shortlink-go/internal/dto"
 	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
 	"shortlink-go/internal/repository"
 	"shortlink-go/internal/service"
+	"shortlink-go/pkg/botdetect"
 	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/utils"
+	"shortlink-go/pkg/visitorid"
 	"shortlink-go/response"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func CreateShortLinkHandler(c *gin.Context) {
@@ -32,9 +40,10 @@ func CreateShortLinkHandler(c *gin.Context) {
 		return
 	}
 
-	zap.L().Info("Request Headers", zap.Any("headers", c.Request.Header))
+	zap.L().Info("Request Headers", zap.Any("headers", loggableHeaders(c)))
 
-	if err := service.CreateShortLink(c.Request.Context(), req); err != nil {
+	result, err := service.CreateShortLink(c.Request.Context(), req)
+	if err != nil {
 		// 记录关键业务参数和错误上下文
 		logging.Logger.Warn("Short chain creation failed",
 			zap.Error(err),
@@ -43,8 +52,36 @@ func CreateShortLinkHandler(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
-	message := i18n.T(c.Request.Context(), "success.short_link_created", nil)
-	c.JSON(http.StatusOK, response.OK("", message))
+
+	messageKey := "success.short_link_created"
+	if result.Reused {
+		messageKey = "success.short_link_reused"
+	} else if len(result.Duplicates) > 0 {
+		messageKey = "success.short_link_created_with_duplicates"
+	}
+	message := i18n.T(c.Request.Context(), messageKey, nil)
+	c.JSON(http.StatusOK, response.OK(result, message))
+}
+
+// PreviewTargetURLHandler 预览合并 UTM 参数后的跳转地址（POST /api/shortlink/preview）
+func PreviewTargetURLHandler(c *gin.Context) {
+	var req dto.PreviewTargetURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	finalURL, err := service.PreviewTargetURL(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(gin.H{
+		"targetUrl": req.TargetURL,
+		"finalUrl":  finalURL,
+	}, "success"))
 }
 
 // ListShortLinksHandler 分页查询短链列表
@@ -52,31 +89,11 @@ func ListShortLinksHandler(c *gin.Context) {
 	// 获取分页参数
 	pageStr := c.DefaultQuery("page", "1")
 	sizeStr := c.DefaultQuery("size", "10")
-	shortCode := c.Query("shortCode")
-	targetUrl := c.Query("targetUrl")
-
-	// 获取 redirectCode，并转换为 int
-	redirectCodeStr := c.Query("redirectCode")
-	var redirectCode int
-	if redirectCodeStr != "" {
-		var err error
-		redirectCode, err = strconv.Atoi(redirectCodeStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid redirectCode"})
-			return
-		}
-	}
 
-	// 获取 disabled，并转换为 bool
-	disabledStr := c.Query("disabled")
-	var disabled *bool // 用指针以区分“未传”和“传了 false”
-	if disabledStr != "" {
-		value, err := strconv.ParseBool(disabledStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid disabled"})
-			return
-		}
-		disabled = &value
+	query, err := parseShortLinkQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
 
 	// 参数转换
@@ -94,8 +111,14 @@ func ListShortLinksHandler(c *gin.Context) {
 		return
 	}
 
+	// 携带 cursor 参数（第一页传空值）时使用游标分页
+	var cursor *string
+	if value, ok := c.GetQuery("cursor"); ok {
+		cursor = &value
+	}
+
 	// 调用服务层
-	pageResp, err := service.ListShortLinks(c.Request.Context(), page, size, shortCode, targetUrl, redirectCode, disabled)
+	pageResp, err := service.ListShortLinks(c.Request.Context(), page, size, query, cursor)
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -105,6 +128,105 @@ func ListShortLinksHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, response.OK(pageResp, "success"))
 }
 
+// parseShortLinkQuery 解析短链列表的筛选参数（列表与导出共用）
+func parseShortLinkQuery(c *gin.Context) (dto.ShortLinkQuery, error) {
+	query := dto.ShortLinkQuery{
+		ShortCode:      c.Query("shortCode"),
+		ShortCodeMatch: c.Query("shortCodeMatch"),
+		TargetURL:      c.Query("targetUrl"),
+		TagMode:        c.Query("tagMode"),
+		SortBy:         c.Query("sortBy"),
+		SortOrder:      c.Query("sortOrder"),
+	}
+
+	// 获取 redirectCode，并转换为 int
+	if redirectCodeStr := c.Query("redirectCode"); redirectCodeStr != "" {
+		redirectCode, err := strconv.Atoi(redirectCodeStr)
+		if err != nil {
+			return query, apperrors.InvalidRequestError("invalid redirectCode")
+		}
+		query.RedirectCode = redirectCode
+	}
+
+	// 获取 disabled，并转换为 bool（用指针以区分“未传”和“传了 false”）
+	if disabledStr := c.Query("disabled"); disabledStr != "" {
+		value, err := strconv.ParseBool(disabledStr)
+		if err != nil {
+			return query, apperrors.InvalidRequestError("invalid disabled")
+		}
+		query.Disabled = &value
+	}
+
+	// 获取 tags（逗号分隔）与 tagMode
+	if tagsStr := c.Query("tags"); tagsStr != "" {
+		for _, tag := range strings.Split(tagsStr, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				query.Tags = append(query.Tags, tag)
+			}
+		}
+	}
+
+	// 创建/更新时间范围，支持 yyyy-MM-dd（结束日期包含当天）或 RFC3339
+	for _, r := range []struct {
+		param    string
+		target   **time.Time
+		endOfDay bool
+	}{
+		{"createdFrom", &query.CreatedFrom, false},
+		{"createdTo", &query.CreatedTo, true},
+		{"updatedFrom", &query.UpdatedFrom, false},
+		{"updatedTo", &query.UpdatedTo, true},
+	} {
+		value := c.Query(r.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseQueryTime(value, r.endOfDay)
+		if err != nil {
+			message := i18n.T(c.Request.Context(), "error.date_range_invalid", nil)
+			return query, apperrors.InvalidRequestError(message)
+		}
+		*r.target = &parsed
+	}
+
+	if err := query.Validate(); err != nil {
+		message := i18n.T(c.Request.Context(), err.Error(), nil)
+		return query, apperrors.InvalidRequestError(message)
+	}
+
+	return query, nil
+}
+
+// parseQueryTime 解析查询参数中的时间，纯日期作为结束时间时取当天最后一刻
+func parseQueryTime(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			parsed = parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return parsed, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// GetShortLinkHandler 查询短链详情（GET /api/shortlink/:id），通过 ETag 返回当前版本号
+func GetShortLinkHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	shortLink, err := service.GetShortLink(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", formatETag(shortLink.Version))
+	c.JSON(http.StatusOK, response.OK(shortLink, "success"))
+}
+
 // UpdateShortLinkHandler 更新短链配置
 func UpdateShortLinkHandler(c *gin.Context) {
 	//绑定请求体到 DTO
@@ -115,20 +237,108 @@ func UpdateShortLinkHandler(c *gin.Context) {
 		return
 	}
 
+	// 乐观锁：必须通过 If-Match 请求头或 version 字段携带版本号
+	expectedVersion, fromHeader, err := requireExpectedVersion(c, req.Version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
 	//调用服务层更新逻辑
-	if err := service.UpdateShortLink(c.Request.Context(), req.ID, req.TargetURL, req.RedirectCode, req.Disabled); err != nil {
+	shortLink, err := service.UpdateShortLink(c.Request.Context(), req.ID, req.TargetURL, req.RedirectCode, req.Disabled, expectedVersion)
+	if err != nil {
 		// 记录关键业务参数和错误上下文
 		zap.L().Warn("Short chain update failed",
 			zap.Error(err),
 			zap.Uint("id", req.ID),
 			zap.String("target_url", req.TargetURL),
 		)
-		_ = c.Error(err)
+		_ = c.Error(preconditionError(err, fromHeader))
 		return
 	}
 
 	// 5. 返回成功响应
-	c.JSON(http.StatusOK, response.OK("", "Short chain update successful"))
+	c.Header("ETag", formatETag(shortLink.Version))
+	c.JSON(http.StatusOK, response.OK(shortLink, "Short chain update successful"))
+}
+
+// PatchShortLinkHandler 部分更新短链（PATCH /api/shortlink/:id，JSON Merge Patch）
+func PatchShortLinkHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	var req dto.PatchShortLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	// 乐观锁：必须通过 If-Match 请求头或 version 字段携带版本号
+	var bodyVersion *uint
+	if req.Version.Set && !req.Version.Null {
+		bodyVersion = &req.Version.Value
+	}
+	expectedVersion, fromHeader, err := requireExpectedVersion(c, bodyVersion)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	shortLink, err := service.PatchShortLink(c.Request.Context(), uint(id), req, expectedVersion)
+	if err != nil {
+		zap.L().Warn("Short chain patch failed",
+			zap.Error(err),
+			zap.Uint("id", uint(id)),
+		)
+		_ = c.Error(preconditionError(err, fromHeader))
+		return
+	}
+
+	c.Header("ETag", formatETag(shortLink.Version))
+	c.JSON(http.StatusOK, response.OK(shortLink, "Short chain update successful"))
+}
+
+// RenameShortCodeHandler 修改短码（POST /api/shortlink/:id/rename）
+func RenameShortCodeHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	var req dto.RenameShortCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	expectedVersion, fromHeader, err := requireExpectedVersion(c, req.Version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	shortLink, err := service.RenameShortCode(c.Request.Context(), uint(id), req.NewShortCode, req.KeepAlias, expectedVersion)
+	if err != nil {
+		zap.L().Warn("Short code rename failed",
+			zap.Error(err),
+			zap.Uint("id", uint(id)),
+			zap.String("new_short_code", req.NewShortCode),
+		)
+		_ = c.Error(preconditionError(err, fromHeader))
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.short_code_renamed", nil)
+	c.Header("ETag", formatETag(shortLink.Version))
+	c.JSON(http.StatusOK, response.OK(shortLink, message))
 }
 
 func RedirectToTargetURLHandler(c *gin.Context) {
@@ -136,8 +346,21 @@ func RedirectToTargetURLHandler(c *gin.Context) {
 	path := c.Request.URL.Path[1:] // 例如 /f/test3 → f/test3
 	ip := c.ClientIP()
 
-	// 查询缓存或数据库
-	shortLink, ok := service.RedirectToTargetURL(path, ip)
+	// 以 + 结尾时展示预览页面（短码不允许包含 +，不会与已有短码冲突）
+	if strings.HasSuffix(path, previewSuffix) {
+		previewShortLink(c, strings.TrimSuffix(path, previewSuffix))
+		return
+	}
+
+	// 查询缓存或数据库（支持别名与路径前缀匹配）
+	shortLink, matchedCode, suffix, ok := service.ResolveShortLink(path)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	// 生成最终跳转地址；历史数据中非 http/https 的地址不跳转、不计数
+	targetURL, ok := safeRedirectURL(c, shortLink, suffix)
 	if !ok {
 		c.Status(http.StatusNotFound)
 		return
@@ -155,15 +378,78 @@ func RedirectToTargetURLHandler(c *gin.Context) {
 		}
 	}()
 
-	// 记录访问统计
-	service.RecordDailyPV(conn, path)
-	service.RecordDailyUV(conn, path, ip)
-	service.RecordTotalPV(conn, path)
-	service.RecordTotalUV(conn, path, ip)
+	// 记录访问统计（别名访问统一计入主短码），社交平台抓取、机器人和预取请求只计入机器流量，内部网络访问只计入内部访问
+	shortCode := shortLink.ShortCode
+	crawler := service.IsSocialCrawler(c.GetHeader("User-Agent"))
+	if crawler {
+		service.RecordBotPV(conn, shortCode)
+	} else if reason := service.ClassifyBot(c.Request); reason != botdetect.ReasonNone {
+		logging.Logger.Debug("Bot request excluded from stats",
+			zap.String("short_code", shortCode),
+			zap.String("reason", string(reason)),
+		)
+		service.RecordBotPV(conn, shortCode)
+	} else if service.IsInternalIP(ip, shortLink) {
+		// 内部网络（全局或短链自身配置的办公室、QA 网段）的访问照常跳转，但单独计数
+		service.RecordInternalPV(conn, shortCode)
+	} else if service.IsTrackingOptedOut(c.Request) {
+		// 拒绝跟踪（DNT/GPC）的访问只计入匿名 PV，不记录 UV、不设置访客 Cookie
+		service.RecordDailyPV(conn, shortCode)
+		service.RecordTotalPV(conn, shortCode)
+		service.RecordOptOutPV(conn, shortCode)
+		if matchedCode != shortCode {
+			service.RecordAliasPV(conn, matchedCode)
+		}
+	} else {
+		// UV 使用按隐私策略处理后的访客标识，原始 IP 不写入 Redis
+		visitor := visitorID(c, ip)
+		service.RecordDailyPV(conn, shortCode)
+		service.RecordDailyUV(conn, shortCode, visitor)
+		service.RecordTotalPV(conn, shortCode)
+		service.RecordTotalUV(conn, shortCode, visitor)
+		if matchedCode != shortCode {
+			// 通过别名访问时额外记录别名维度的统计
+			service.RecordAliasPV(conn, matchedCode)
+			service.RecordAliasUV(conn, matchedCode, visitor)
+		}
+	}
 
-	// 获取目标 URL 和状态码
+	// 获取状态码
 	redirectCode := shortLink.RedirectCode
-	targetURL := shortLink.TargetURL
+
+	// 社交平台抓取且配置了预览信息时返回 Open Graph 页面，否则按正常方式跳转，由抓取程序读取目标页面
+	if crawler && !shortLink.OpenGraph.IsEmpty() {
+		page, err := service.RenderOpenGraphPage(shortLink, service.PublicShortLinkURL(c.Request.URL.EscapedPath()), targetURL)
+		if err == nil {
+			for name, value := range service.RedirectPageHeaders("og") {
+				c.Header(name, value)
+			}
+			c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+			return
+		}
+		logging.Logger.Error("Failed to render Open Graph page",
+			zap.Error(err),
+			zap.String("short_code", shortCode),
+		)
+	}
+
+	// meta/js/frame 方式返回 HTML 页面，由浏览器完成跳转
+	if service.IsHTMLRedirectMode(shortLink.RedirectMode) {
+		page, err := service.RenderRedirectPage(shortLink, targetURL)
+		if err == nil {
+			for name, value := range service.RedirectPageHeaders(shortLink.RedirectMode) {
+				c.Header(name, value)
+			}
+			c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+			return
+		}
+		// 渲染失败时退回普通的状态码跳转
+		logging.Logger.Error("Failed to render redirect page",
+			zap.Error(err),
+			zap.String("short_code", shortCode),
+			zap.String("redirect_mode", shortLink.RedirectMode),
+		)
+	}
 
 	// 设置响应头（仅在 302 时）
 	if redirectCode == http.StatusFound {
@@ -173,6 +459,130 @@ func RedirectToTargetURLHandler(c *gin.Context) {
 	c.Redirect(redirectCode, targetURL)
 }
 
+// safeRedirectURL 生成短链的最终跳转地址，并确认其协议为 http/https，防止 javascript: 等地址进入跳转页面
+func safeRedirectURL(c *gin.Context, shortLink *model.ShortLink, suffix string) (string, bool) {
+	targetURL := service.BuildRedirectURL(shortLink, newRedirectContext(c, suffix))
+	if !utils.IsHTTPURL(targetURL) {
+		logging.Logger.Warn("Refused to redirect to non-http target URL",
+			zap.String("short_code", shortLink.ShortCode),
+		)
+		return "", false
+	}
+	return targetURL, true
+}
+
+// previewSuffix 短链地址后追加该后缀时展示预览页面而不跳转
+const previewSuffix = "+"
+
+// previewShortLink 展示短链的预览页面，不记录访问统计
+func previewShortLink(c *gin.Context, path string) {
+	shortLink, matchedCode, suffix, ok := service.ResolveShortLink(path)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	targetURL, ok := safeRedirectURL(c, shortLink, suffix)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	page, err := service.RenderPreviewPage(c.Request.Context(), shortLink, matchedCode, targetURL)
+	if err != nil {
+		logging.Logger.Error("Failed to render preview page",
+			zap.Error(err),
+			zap.String("short_code", matchedCode),
+		)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	for name, value := range service.RedirectPageHeaders("preview") {
+		c.Header(name, value)
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
+
+// ipHeaders 携带客户端 IP 的请求头，开启日志脱敏时不写入日志
+var ipHeaders = []string{"Forwarded", "X-Forwarded-For", "X-Real-IP", "CF-Connecting-IP", "True-Client-IP"}
+
+// loggableHeaders 返回可以写入日志的请求头
+func loggableHeaders(c *gin.Context) http.Header {
+	if !service.AnonymizeLogs() {
+		return c.Request.Header
+	}
+	headers := c.Request.Header.Clone()
+	for _, name := range ipHeaders {
+		headers.Del(name)
+	}
+	return headers
+}
+
+// visitorID 按 privacy.visitor_id 配置生成 UV 统计使用的访客标识
+func visitorID(c *gin.Context, ip string) string {
+	switch service.VisitorIDStrategy() {
+	case service.VisitorIDHash:
+		return hashedVisitorID(c, ip)
+	case service.VisitorIDTruncate:
+		return service.TruncateIP(ip)
+	case service.VisitorIDCookie:
+		name := service.VisitorCookieName()
+		if id, err := c.Cookie(name); err == nil && visitorid.IsValidCookieID(id) {
+			return id
+		}
+		id, err := visitorid.NewCookieID()
+		if err != nil {
+			logging.Logger.Error("Failed to generate visitor cookie", zap.Error(err))
+			return hashedVisitorID(c, ip)
+		}
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(name, id, service.VisitorCookieMaxAge(), "/", "", requestScheme(c) == "https", true)
+		return id
+	default:
+		return ip
+	}
+}
+
+// hashedVisitorID 计算 IP 与 User-Agent 的访客标识，salt 不可用时退回截断网段，避免写入原始 IP
+func hashedVisitorID(c *gin.Context, ip string) string {
+	id, err := service.HashVisitorID(ip, c.GetHeader("User-Agent"))
+	if err != nil {
+		logging.Logger.Error("Failed to hash visitor id", zap.Error(err))
+		return service.TruncateIP(ip)
+	}
+	return id
+}
+
+// requestScheme 返回访问短链使用的协议，只有直接连接方是受信任代理时才采用 X-Forwarded-Proto
+func requestScheme(c *gin.Context) string {
+	if c.GetBool(constant.TrustedProxyContextKey) {
+		if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			return proto
+		}
+	}
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// newRedirectContext 收集生成最终跳转地址所需的请求信息
+func newRedirectContext(c *gin.Context, suffix string) service.RedirectContext {
+	rc := service.RedirectContext{
+		Suffix:   suffix,
+		RawQuery: c.Request.URL.RawQuery,
+		Query:    c.Request.URL.Query(),
+		Language: utils.PrimaryLanguage(c.GetHeader("Accept-Language")),
+	}
+	for _, header := range service.CountryHeaders() {
+		if country := c.GetHeader(header); country != "" {
+			rc.Country = country
+			break
+		}
+	}
+	return rc
+}
+
 func DeleteShortLinkHandler(c *gin.Context) {
 	// 1. 从 URL 路径中提取短链 ID
 	idStr := c.Param("id")
@@ -196,3 +606,54 @@ func DeleteShortLinkHandler(c *gin.Context) {
 	message := i18n.T(c.Request.Context(), "success.short_link_deleted", nil)
 	c.JSON(http.StatusOK, response.OK("", message))
 }
+
+// formatETag 将短链版本号格式化为强校验 ETag
+func formatETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// requireExpectedVersion 解析期望版本号，未通过 If-Match 请求头或 version 字段携带版本号时返回 428
+// If-Match: * 表示匹配任意当前版本，返回 0 由服务层以读取到的版本为准
+func requireExpectedVersion(c *gin.Context, bodyVersion *uint) (uint, bool, error) {
+	expectedVersion, fromHeader, err := resolveExpectedVersion(c, bodyVersion)
+	if err != nil {
+		return 0, fromHeader, err
+	}
+	if expectedVersion == 0 && !fromHeader {
+		message := i18n.T(c.Request.Context(), "error.version_required", nil)
+		return 0, false, apperrors.BusinessError(http.StatusPreconditionRequired, message)
+	}
+	return expectedVersion, fromHeader, nil
+}
+
+// resolveExpectedVersion 解析期望版本号：优先使用 If-Match 请求头，其次使用请求体中的 version 字段
+// 返回 0 表示不校验具体版本：未携带版本号时 fromHeader 为 false，If-Match: * 时为 true
+func resolveExpectedVersion(c *gin.Context, bodyVersion *uint) (uint, bool, error) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" {
+		if bodyVersion == nil {
+			return 0, false, nil
+		}
+		return *bodyVersion, false, nil
+	}
+	if ifMatch == "*" {
+		return 0, true, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil || version < 1 {
+		message := i18n.T(c.Request.Context(), "error.if_match_invalid", nil)
+		return 0, true, apperrors.InvalidRequestError(message)
+	}
+	return uint(version), true, nil
+}
+
+// preconditionError 版本号来自 If-Match 请求头时，按 HTTP 语义将版本冲突转换为 412，其他 409（如短码已存在）保持不变
+func preconditionError(err error, fromHeader bool) error {
+	var appErr *apperrors.AppError
+	if fromHeader && errors.Is(err, service.ErrVersionConflict) && errors.As(err, &appErr) {
+		return apperrors.BusinessError(http.StatusPreconditionFailed, appErr.Message)
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"path/filepath"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/service"
+	"shortlink-go/response"
+	"strconv"
+	"strings"
+)
+
+// maxImportBodySize 导入文件大小上限（64MB）
+const maxImportBodySize = 64 << 20
+
+// ImportShortLinksHandler 批量导入短链（POST /api/shortlink/import?format=csv|ndjson&dryRun=true&onConflict=skip|overwrite|fail）
+// 请求体为文件原始内容，或 multipart/form-data 中的 file 字段
+func ImportShortLinksHandler(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		_ = c.Error(apperrors.InvalidRequestError("invalid dryRun"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodySize)
+
+	format := strings.ToLower(c.Query("format"))
+	var reader io.Reader = c.Request.Body
+
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+			_ = c.Error(apperrors.InvalidRequestError(message))
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			_ = c.Error(apperrors.SystemErrorDefault())
+			return
+		}
+		defer func() {
+			if err := file.Close(); err != nil {
+				zap.L().Warn("Failed to close import file", zap.Error(err))
+			}
+		}()
+
+		reader = file
+		if format == "" {
+			format = importFormatFromName(fileHeader.Filename)
+		}
+	} else if format == "" {
+		format = importFormatFromContentType(c.ContentType())
+	}
+
+	report, err := service.ImportShortLinks(c.Request.Context(), format, reader, dryRun, c.Query("onConflict"))
+	if err != nil {
+		zap.L().Warn("Short link import failed",
+			zap.Error(err),
+			zap.String("format", format),
+			zap.Bool("dry_run", dryRun),
+		)
+		_ = c.Error(err)
+		return
+	}
+
+	if report.Aborted {
+		message := i18n.T(c.Request.Context(), "error.import_aborted", nil)
+		c.JSON(http.StatusConflict, response.Fail(report, message))
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.short_links_imported", nil)
+	c.JSON(http.StatusOK, response.OK(report, message))
+}
+
+// importFormatFromContentType 根据 Content-Type 推断导入格式
+func importFormatFromContentType(contentType string) string {
+	switch contentType {
+	case "text/csv", "application/csv":
+		return service.ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return service.ImportFormatNDJSON
+	default:
+		return ""
+	}
+}
+
+// importFormatFromName 根据上传文件的扩展名推断导入格式
+func importFormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return service.ImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return service.ImportFormatNDJSON
+	default:
+		return ""
+	}
+}
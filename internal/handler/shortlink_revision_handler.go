@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/service"
+	"shortlink-go/response"
+	"strconv"
+)
+
+// ListShortLinkRevisionsHandler 查询短链历史版本（GET /api/shortlink/:id/revisions）
+func ListShortLinkRevisionsHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	revisions, err := service.ListShortLinkRevisions(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(revisions, "success"))
+}
+
+// RollbackShortLinkHandler 回滚短链到指定历史版本（POST /api/shortlink/:id/revisions/:revision/rollback）
+func RollbackShortLinkHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	revision, err := strconv.ParseUint(c.Param("revision"), 10, 64)
+	if err != nil || revision < 1 {
+		message := i18n.T(c.Request.Context(), "error.revision_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	// 请求体可省略，仅携带 If-Match 请求头
+	var req dto.RollbackShortLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	// 乐观锁：与 PUT/PATCH 一致，必须通过 If-Match 请求头或 version 字段携带版本号
+	expectedVersion, fromHeader, err := requireExpectedVersion(c, req.Version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	shortLink, err := service.RollbackShortLink(c.Request.Context(), uint(id), uint(revision), expectedVersion)
+	if err != nil {
+		zap.L().Warn("Short chain rollback failed",
+			zap.Error(err),
+			zap.Uint("id", uint(id)),
+			zap.Uint("revision", uint(revision)),
+		)
+		_ = c.Error(preconditionError(err, fromHeader))
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.short_link_rolled_back", nil)
+	c.Header("ETag", formatETag(shortLink.Version))
+	c.JSON(http.StatusOK, response.OK(shortLink, message))
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"shortlink-go/internal/service"
+	"shortlink-go/response"
+	"strconv"
+)
+
+// SearchShortLinksHandler 全文检索短链（GET /api/shortlink/search?q=xxx&limit=20）
+func SearchShortLinksHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	results, err := service.SearchShortLinks(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(results, "success"))
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/service"
+	"shortlink-go/response"
+	"strconv"
+)
+
+// GetShortLinkStatsHandler 查询短链统计（GET /api/shortlink/:id/stats?from=yyyy-MM-dd&to=yyyy-MM-dd）
+func GetShortLinkStatsHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	stats, err := service.GetShortLinkStats(c.Request.Context(), uint(id), c.Query("from"), c.Query("to"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(stats, "success"))
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/service"
+	"shortlink-go/response"
+	"strconv"
+)
+
+// ListTrashedShortLinksHandler 分页查询回收站（GET /api/shortlink/trash）
+func ListTrashedShortLinksHandler(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		message := i18n.T(c.Request.Context(), "error.page_number_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	size, err := strconv.Atoi(c.DefaultQuery("size", "10"))
+	if err != nil || size < 1 || size > 100 {
+		message := i18n.T(c.Request.Context(), "error.page_size_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	pageResp, err := service.ListTrashedShortLinks(c.Request.Context(), page, size)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(pageResp, "success"))
+}
+
+// RestoreShortLinkHandler 从回收站恢复短链（POST /api/shortlink/:id/restore）
+func RestoreShortLinkHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	shortLink, err := service.RestoreShortLink(c.Request.Context(), uint(id))
+	if err != nil {
+		zap.L().Warn("Short link restore failed",
+			zap.Error(err),
+			zap.Uint("id", uint(id)),
+		)
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", formatETag(shortLink.Version))
+	message := i18n.T(c.Request.Context(), "success.short_link_restored", nil)
+	c.JSON(http.StatusOK, response.OK(shortLink, message))
+}
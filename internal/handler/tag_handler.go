@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/service"
+	"shortlink-go/response"
+	"strconv"
+)
+
+// ListTagsHandler 查询标签列表（GET /api/tag?name=xxx）
+func ListTagsHandler(c *gin.Context) {
+	tags, err := service.ListTags(c.Request.Context(), c.Query("name"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(tags, "success"))
+}
+
+// CreateTagHandler 创建标签（POST /api/tag）
+func CreateTagHandler(c *gin.Context) {
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	tag, err := service.CreateTag(c.Request.Context(), req)
+	if err != nil {
+		zap.L().Warn("Tag creation failed",
+			zap.Error(err),
+			zap.String("name", req.Name),
+		)
+		_ = c.Error(err)
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.tag_created", nil)
+	c.JSON(http.StatusOK, response.OK(tag, message))
+}
+
+// UpdateTagHandler 修改标签（PATCH /api/tag/:id）
+func UpdateTagHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		message := i18n.T(c.Request.Context(), "error.request_body_invalid", nil)
+		_ = c.Error(apperrors.InvalidRequestError(message))
+		return
+	}
+
+	tag, err := service.UpdateTag(c.Request.Context(), uint(id), req)
+	if err != nil {
+		zap.L().Warn("Tag update failed",
+			zap.Error(err),
+			zap.Uint("id", uint(id)),
+		)
+		_ = c.Error(err)
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.tag_updated", nil)
+	c.JSON(http.StatusOK, response.OK(tag, message))
+}
+
+// DeleteTagHandler 删除标签（DELETE /api/tag/:id）
+func DeleteTagHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	if err := service.DeleteTag(c.Request.Context(), uint(id)); err != nil {
+		zap.L().Warn("Tag deletion failed",
+			zap.Error(err),
+			zap.Uint("id", uint(id)),
+		)
+		_ = c.Error(err)
+		return
+	}
+
+	message := i18n.T(c.Request.Context(), "success.tag_deleted", nil)
+	c.JSON(http.StatusOK, response.OK("", message))
+}
+
+// GetTagStatsHandler 查询标签汇总统计（GET /api/tag/:id/stats?from=yyyy-MM-dd&to=yyyy-MM-dd）
+func GetTagStatsHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		message := i18n.T(c.Request.Context(), "error.invalid_id", nil)
+		_ = c.Error(apperrors.BusinessError(http.StatusBadRequest, message))
+		return
+	}
+
+	stats, err := service.GetTagStats(c.Request.Context(), uint(id), c.Query("from"), c.Query("to"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(stats, "success"))
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net"
+	"shortlink-go/constant"
+	"shortlink-go/pkg/clientip"
+)
+
+// ClientIPMiddleware 按受信任代理配置解析真实客户端 IP，并写回 Request.RemoteAddr
+// 需配合 engine.SetTrustedProxies(nil) 使用，之后 c.ClientIP() 直接返回解析结果，不再由 gin 读取转发请求头
+// 改写前在上下文中记录直接连接方是否为受信任代理（constant.TrustedProxyContextKey），供读取其他转发请求头时判断
+func ClientIPMiddleware(resolver *clientip.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		peer, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+		if err != nil {
+			peer = c.Request.RemoteAddr
+		}
+		c.Set(constant.TrustedProxyContextKey, resolver.IsTrusted(net.ParseIP(peer)))
+
+		if ip := resolver.Resolve(c.Request); ip != "" {
+			_, port, err := net.SplitHostPort(c.Request.RemoteAddr)
+			if err != nil {
+				port = "0"
+			}
+			c.Request.RemoteAddr = net.JoinHostPort(ip, port)
+		}
+		c.Next()
+	}
+}
@@ -12,10 +12,13 @@ func CorsMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 
 		// 设置允许的请求头
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+
+		// 允许前端读取 ETag（乐观锁版本号）
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 		// 设置允许的方法
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 
 		// 如果是预检请求（OPTIONS），直接返回 204
 		if c.Request.Method == "OPTIONS" {
@@ -6,17 +6,23 @@ import (
 	"time"
 )
 
-func ZapGinLogger(logger *zap.Logger) gin.HandlerFunc {
+// ZapGinLogger 记录请求日志，anonymizeIP 不为 nil 时客户端 IP 先经其脱敏再写入日志
+func ZapGinLogger(logger *zap.Logger, anonymizeIP func(ip string) string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		c.Next()
 		latency := time.Since(start)
 
+		clientIP := c.ClientIP()
+		if anonymizeIP != nil {
+			clientIP = anonymizeIP(clientIP)
+		}
+
 		logger.Info("HTTP Request",
 			zap.String("path", c.Request.URL.Path),
 			zap.String("method", c.Request.Method),
 			zap.Int("status", c.Writer.Status()),
-			zap.String("client_ip", c.ClientIP()),
+			zap.String("client_ip", clientIP),
 			zap.Duration("latency", latency),
 		)
 	}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"math"
+	"net"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/ratelimit"
+	"strconv"
+	"time"
+)
+
+// RateLimitKeyFunc 从请求中提取限流维度的值，返回空字符串表示该请求不按此维度限流
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitRule 一条限流规则：同一维度值在 Window 内最多 Limit 次请求
+type RateLimitRule struct {
+	Name   string // 维度名称，如 ip、code
+	Limit  int
+	Window time.Duration
+	Key    RateLimitKeyFunc
+}
+
+// RateLimitMiddleware 按规则依次限流，任一规则超限时返回 429 并设置 Retry-After
+// group 区分不同路由组的计数，exempt 返回 true 的客户端 IP（如内部网络）不受限制
+func RateLimitMiddleware(limiter ratelimit.Limiter, group string, rules []RateLimitRule, exempt func(ip net.IP) bool) gin.HandlerFunc {
+	allow := RateLimitCheck(limiter, group, rules, exempt)
+	return func(c *gin.Context) {
+		if allow(c) {
+			c.Next()
+		}
+	}
+}
+
+// RateLimitCheck 与 RateLimitMiddleware 规则相同，但不调用 c.Next()：返回 false 时已写入 429 错误并中止请求
+// 用于只对部分请求（如全局中间件中的 GET/HEAD 跳转）限流的场景
+func RateLimitCheck(limiter ratelimit.Limiter, group string, rules []RateLimitRule, exempt func(ip net.IP) bool) func(c *gin.Context) bool {
+	return func(c *gin.Context) bool {
+		if exempt != nil {
+			if ip := net.ParseIP(c.ClientIP()); ip != nil && exempt(ip) {
+				return true
+			}
+		}
+
+		for _, rule := range rules {
+			value := rule.Key(c)
+			if value == "" {
+				continue
+			}
+
+			result, err := limiter.Allow(c.Request.Context(), group+":"+rule.Name+":"+value, rule.Limit, rule.Window)
+			if err != nil {
+				// 限流器不可用时放行，不影响正常访问
+				logging.Logger.Error("Rate limiter unavailable", zap.String("group", group), zap.Error(err))
+				continue
+			}
+			if result.Allowed {
+				continue
+			}
+
+			logging.Logger.Warn("Rate limit exceeded",
+				zap.String("group", group),
+				zap.String("rule", rule.Name),
+				zap.String("path", c.Request.URL.Path),
+			)
+			seconds := int(math.Ceil(result.RetryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			message := i18n.T(c.Request.Context(), "error.too_many_requests", nil)
+			_ = c.Error(apperrors.BusinessError(http.StatusTooManyRequests, message))
+			c.Abort()
+			return false
+		}
+		return true
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// CIDRList 以逗号分隔存入单列的 CIDR 或 IP 列表，接口中为字符串数组
+type CIDRList []string
+
+// GormDataType 按字符串列建表，长度由 size 标签决定
+func (CIDRList) GormDataType() string {
+	return "string"
+}
+
+// Value 实现 driver.Valuer
+func (l CIDRList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Scan 实现 sql.Scanner
+func (l *CIDRList) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported CIDRList value type %T", value)
+	}
+
+	if raw == "" {
+		*l = nil
+		return nil
+	}
+	*l = strings.Split(raw, ",")
+	return nil
+}
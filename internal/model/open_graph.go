@@ -0,0 +1,22 @@
+package model
+
+// OpenGraph 社交平台抓取短链时展示的预览信息，全部为空时不生成预览页面
+type OpenGraph struct {
+	Title       string `gorm:"size:256" json:"title"`
+	Description string `gorm:"size:512" json:"description"`
+	Image       string `gorm:"size:2048" json:"image"`
+}
+
+// IsEmpty 判断是否未配置任何预览信息
+func (o OpenGraph) IsEmpty() bool {
+	return o.Title == "" && o.Description == "" && o.Image == ""
+}
+
+// Values 返回全部字段，用于按列更新
+func (o OpenGraph) Values() map[string]interface{} {
+	return map[string]interface{}{
+		"og_title":       o.Title,
+		"og_description": o.Description,
+		"og_image":       o.Image,
+	}
+}
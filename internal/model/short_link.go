@@ -1,12 +1,71 @@
 package model
 
+import (
+	"shortlink-go/pkg/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type ShortLink struct {
 	BaseModel
-	ShortCode    string `gorm:"uniqueIndex;size:32;not null" json:"shortCode"`
-	TargetURL    string `gorm:"size:2048;not null" json:"targetUrl"`
-	RedirectCode int    `gorm:"default:302" json:"redirectCode"`
-	Disabled     bool   `json:"disabled" json:"disabled"`
-	TotalPV      uint64 `gorm:"default:0" json:"totalPv"`
-	TotalUV      uint64 `gorm:"default:0" json:"totalUv"`
-	UvHLLBackup  []byte `gorm:"type:blob" json:"-"`
+	ShortCode     string     `gorm:"uniqueIndex;index:idx_short_links_fulltext,class:FULLTEXT,option:WITH PARSER ngram;size:32;not null" json:"shortCode"`
+	TargetURL     string     `gorm:"size:2048;not null;index:idx_short_links_fulltext,class:FULLTEXT,option:WITH PARSER ngram" json:"targetUrl"`
+	CanonicalHash string     `gorm:"size:64;index" json:"-"` // 规范化目标地址的 SHA-256，用于发现重复目标
+	RedirectCode  int        `gorm:"default:302" json:"redirectCode"`
+	RedirectMode  string     `gorm:"size:16;default:http" json:"redirectMode"` // 跳转方式，见 RedirectMode* 常量
+	Disabled      bool       `json:"disabled" json:"disabled"`
+	ExpiresAt     *time.Time `json:"expiresAt"`                                                                                          // 过期时间，为空表示永不过期
+	Description   string     `gorm:"size:512;index:idx_short_links_fulltext,class:FULLTEXT,option:WITH PARSER ngram" json:"description"` // 简短说明，列表中展示
+	Notes         string     `gorm:"type:text;index:idx_short_links_fulltext,class:FULLTEXT,option:WITH PARSER ngram" json:"notes"`
+	Tags          []Tag      `gorm:"many2many:short_link_tags;" json:"tags"`
+	UTM           UTMParams  `gorm:"embedded;embeddedPrefix:utm_" json:"utm"`
+	OpenGraph     OpenGraph  `gorm:"embedded;embeddedPrefix:og_" json:"openGraph"` // 社交平台抓取时的预览信息
+
+	QueryPassthrough bool           `gorm:"default:false" json:"queryPassthrough"` // 将访问时的查询参数追加到目标地址
+	PathPassthrough  bool           `gorm:"default:false" json:"pathPassthrough"`  // 前缀匹配：短码之后的路径追加到目标地址
+	InternalCIDRs    CIDRList       `gorm:"size:1024" json:"internalCidrs"`        // 本短链额外视为内部网络的 CIDR 或 IP，与 stats.internal_cidrs 合并
+	FinalURL         string         `gorm:"-" json:"finalUrl"`                     // 合并 UTM 参数后实际跳转的地址（只读）
+	TotalPV          uint64         `gorm:"default:0;index" json:"totalPv"`        // 索引用于按访问量排序
+	TotalUV          uint64         `gorm:"default:0;index" json:"totalUv"`
+	BotPV            uint64         `gorm:"default:0" json:"botPv"`      // 机器人、预取等机器流量，不计入 PV/UV
+	InternalPV       uint64         `gorm:"default:0" json:"internalPv"` // 来自内部网络（stats.internal_cidrs）的访问，不计入 PV/UV
+	OptOutPV         uint64         `gorm:"default:0" json:"optOutPv"`   // 携带 DNT/GPC 的访问，计入 PV 但不计入 UV
+	UvHLLBackup      []byte         `gorm:"type:blob" json:"-"`
+	Version          uint           `gorm:"not null;default:1" json:"version"` // 乐观锁版本号，每次编辑递增
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`  // 软删除时间，回收站中的短码仍然保留
+}
+
+// 跳转方式：http 使用 3xx 状态码，其余返回 HTML 页面由浏览器完成跳转
+const (
+	RedirectModeHTTP  = "http"  // 3xx 状态码跳转
+	RedirectModeMeta  = "meta"  // meta refresh 页面，页面中的统计代码可以先执行
+	RedirectModeJS    = "js"    // JavaScript 跳转页面
+	RedirectModeFrame = "frame" // 全屏 iframe 嵌入目标页面，地址栏保留短链地址
+)
+
+// IsExpired 判断短链是否已过期
+func (s *ShortLink) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// FinalTargetURL 跳转时实际使用的地址：在目标地址上追加 UTM 参数，目标地址中已有的同名参数优先
+// 目标地址为模板时，返回的地址中仍保留占位符
+func (s *ShortLink) FinalTargetURL() string {
+	return s.WithUTM(s.TargetURL)
+}
+
+// WithUTM 在给定地址上追加本短链的 UTM 参数
+func (s *ShortLink) WithUTM(target string) string {
+	pairs := s.UTM.Pairs()
+	if len(pairs) == 0 {
+		return target
+	}
+	return utils.MergeQueryParams(target, pairs)
+}
+
+// AfterFind 查询后填充 FinalURL
+func (s *ShortLink) AfterFind(*gorm.DB) error {
+	s.FinalURL = s.FinalTargetURL()
+	return nil
 }
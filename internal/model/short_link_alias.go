@@ -0,0 +1,10 @@
+package model
+
+// ShortLinkAlias 短链别名：额外的短码，跳转到同一条短链
+type ShortLinkAlias struct {
+	BaseModel
+	ShortLinkID uint   `gorm:"index;not null" json:"shortLinkId"`
+	Code        string `gorm:"uniqueIndex;size:32;not null" json:"code"`
+	TotalPV     uint64 `gorm:"default:0" json:"totalPv"` // 通过该别名访问的 PV（同时计入主短链）
+	TotalUV     uint64 `gorm:"default:0" json:"totalUv"`
+}
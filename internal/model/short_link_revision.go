@@ -0,0 +1,15 @@
+package model
+
+// ShortLinkRevision 短链跳转规则的历史版本（每次变更目标地址、状态码、跳转方式、UTM、透传或预览信息时递增）
+type ShortLinkRevision struct {
+	BaseModel
+	ShortLinkID      uint      `gorm:"index:uniq_shortlinkid_revision,unique" json:"shortLinkId"` // part 1 of unique index
+	Revision         uint      `gorm:"index:uniq_shortlinkid_revision,unique" json:"revision"`    // part 2
+	TargetURL        string    `gorm:"size:2048;not null" json:"targetUrl"`
+	RedirectCode     int       `gorm:"default:302" json:"redirectCode"`
+	RedirectMode     string    `gorm:"size:16;default:http" json:"redirectMode"`
+	UTM              UTMParams `gorm:"embedded;embeddedPrefix:utm_" json:"utm"`
+	OpenGraph        OpenGraph `gorm:"embedded;embeddedPrefix:og_" json:"openGraph"`
+	QueryPassthrough bool      `gorm:"default:false" json:"queryPassthrough"`
+	PathPassthrough  bool      `gorm:"default:false" json:"pathPassthrough"`
+}
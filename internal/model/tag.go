@@ -0,0 +1,8 @@
+package model
+
+// Tag 短链标签（与短链为多对多关系，关联表 short_link_tags）
+type Tag struct {
+	BaseModel
+	Name        string `gorm:"size:64;uniqueIndex;index:idx_tags_name_fulltext,class:FULLTEXT,option:WITH PARSER ngram;not null" json:"name"`
+	Description string `gorm:"size:512" json:"description"`
+}
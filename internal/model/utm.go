@@ -0,0 +1,38 @@
+package model
+
+// UTMParams 跳转时追加到目标地址的 UTM 参数，为空的字段不追加
+type UTMParams struct {
+	Source   string `gorm:"size:128" json:"source"`
+	Medium   string `gorm:"size:128" json:"medium"`
+	Campaign string `gorm:"size:128" json:"campaign"`
+	Term     string `gorm:"size:128" json:"term"`
+	Content  string `gorm:"size:128" json:"content"`
+}
+
+// Pairs 按固定顺序返回非空的查询参数
+func (u UTMParams) Pairs() [][2]string {
+	pairs := make([][2]string, 0, 5)
+	for _, p := range [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	} {
+		if p[1] != "" {
+			pairs = append(pairs, p)
+		}
+	}
+	return pairs
+}
+
+// Values 返回全部字段，用于按列更新
+func (u UTMParams) Values() map[string]interface{} {
+	return map[string]interface{}{
+		"utm_source":   u.Source,
+		"utm_medium":   u.Medium,
+		"utm_campaign": u.Campaign,
+		"utm_term":     u.Term,
+		"utm_content":  u.Content,
+	}
+}
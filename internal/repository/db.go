@@ -20,7 +20,7 @@ func InitDB(logger *zap.Logger, atomicLogLevel zap.AtomicLevel) {
 		logging.Logger.Fatal("Failed to connect database", zap.Error(err))
 	}
 
-	err = db.AutoMigrate(&model.ShortLink{}, &model.DailyStat{}, &model.WhitelistDomain{})
+	err = db.AutoMigrate(&model.ShortLink{}, &model.DailyStat{}, &model.WhitelistDomain{}, &model.ShortLinkRevision{}, &model.Tag{}, &model.ShortLinkAlias{})
 	if err != nil {
 		logging.Logger.Fatal("Failed to migrate database", zap.Error(err))
 	}
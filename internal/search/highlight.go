@@ -0,0 +1,112 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// 高亮片段参数
+const (
+	fragmentContext = 30 // 匹配位置前后保留的字符数
+	maxFragments    = 3  // 每个字段最多返回的片段数
+)
+
+// Highlight 返回 text 中命中关键词的片段：匹配部分用 <mark></mark> 包裹，其余内容做 HTML 转义，
+// 被截断的片段首尾以 … 表示；没有命中时返回 nil
+func Highlight(text string, terms []string) []string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	matches := findMatches(lower, terms)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// 将相邻的匹配合并到同一个片段窗口
+	type window struct {
+		start, end int
+		matches    [][2]int
+	}
+	windows := make([]window, 0)
+	for _, m := range matches {
+		start := max(0, m[0]-fragmentContext)
+		end := min(len(runes), m[1]+fragmentContext)
+		if n := len(windows); n > 0 && start <= windows[n-1].end {
+			windows[n-1].end = max(windows[n-1].end, end)
+			windows[n-1].matches = append(windows[n-1].matches, m)
+			continue
+		}
+		if len(windows) == maxFragments {
+			break
+		}
+		windows = append(windows, window{start: start, end: end, matches: [][2]int{m}})
+	}
+
+	fragments := make([]string, 0, len(windows))
+	for _, w := range windows {
+		var b strings.Builder
+		if w.start > 0 {
+			b.WriteString("…")
+		}
+		pos := w.start
+		for _, m := range w.matches {
+			b.WriteString(html.EscapeString(string(runes[pos:m[0]])))
+			b.WriteString("<mark>")
+			b.WriteString(html.EscapeString(string(runes[m[0]:m[1]])))
+			b.WriteString("</mark>")
+			pos = m[1]
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:w.end])))
+		if w.end < len(runes) {
+			b.WriteString("…")
+		}
+		fragments = append(fragments, b.String())
+	}
+	return fragments
+}
+
+// findMatches 查找所有关键词的命中区间（以 rune 为单位），按位置排序且互不重叠
+func findMatches(lower []rune, terms []string) [][2]int {
+	covered := make([]bool, len(lower))
+	for _, term := range terms {
+		t := []rune(term)
+		if len(t) == 0 {
+			continue
+		}
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if runesEqual(lower[i:i+len(t)], t) {
+				for j := i; j < i+len(t); j++ {
+					covered[j] = true
+				}
+			}
+		}
+	}
+
+	matches := make([][2]int, 0)
+	for i := 0; i < len(covered); {
+		if !covered[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(covered) && covered[j] {
+			j++
+		}
+		matches = append(matches, [2]int{i, j})
+		i = j
+	}
+	return matches
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 各字段命中一次的权重
+const (
+	weightShortCode   = 4
+	weightTag         = 3
+	weightDescription = 2
+	weightTargetURL   = 1
+	weightNotes       = 1
+)
+
+// MemorySearcher 基于内存的检索实现：子串匹配，按字段加权的命中次数排序
+type MemorySearcher struct {
+	mu   sync.RWMutex
+	docs map[uint]Document
+}
+
+// NewMemorySearcher 创建内存检索器并索引给定的文档
+func NewMemorySearcher(docs ...Document) *MemorySearcher {
+	m := &MemorySearcher{docs: make(map[uint]Document, len(docs))}
+	for _, doc := range docs {
+		m.docs[doc.ID] = doc
+	}
+	return m
+}
+
+// Index 添加或替换文档
+func (m *MemorySearcher) Index(doc Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.docs[doc.ID] = doc
+}
+
+// Remove 移除文档
+func (m *MemorySearcher) Remove(id uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.docs, id)
+}
+
+// Search 任一关键词命中即返回，得分相同时 ID 大的在前
+func (m *MemorySearcher) Search(_ context.Context, query string, limit int) ([]Hit, error) {
+	terms := Tokenize(query)
+	if len(terms) == 0 || limit <= 0 {
+		return []Hit{}, nil
+	}
+
+	m.mu.RLock()
+	hits := make([]Hit, 0)
+	for _, doc := range m.docs {
+		if score := scoreDocument(doc, terms); score > 0 {
+			hits = append(hits, Hit{ID: doc.ID, Score: score})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func scoreDocument(doc Document, terms []string) float64 {
+	shortCode := strings.ToLower(doc.ShortCode)
+	targetURL := strings.ToLower(doc.TargetURL)
+	description := strings.ToLower(doc.Description)
+	notes := strings.ToLower(doc.Notes)
+
+	var score float64
+	for _, term := range terms {
+		score += float64(weightShortCode * strings.Count(shortCode, term))
+		score += float64(weightDescription * strings.Count(description, term))
+		score += float64(weightTargetURL * strings.Count(targetURL, term))
+		score += float64(weightNotes * strings.Count(notes, term))
+		for _, tag := range doc.Tags {
+			score += float64(weightTag * strings.Count(strings.ToLower(tag), term))
+		}
+	}
+	return score
+}
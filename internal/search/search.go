@@ -0,0 +1,64 @@
+// Package search 提供短链全文检索的公共抽象：检索接口、查询分词与匹配片段高亮。
+// 生产环境使用 MySQL FULLTEXT 索引实现（见 service 包），MemorySearcher 为纯 Go 实现，用于测试或无全文索引的环境。
+package search
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// maxTerms 单次查询最多使用的关键词数量
+const maxTerms = 10
+
+// Document 参与检索的短链字段
+type Document struct {
+	ID          uint
+	ShortCode   string
+	TargetURL   string
+	Description string
+	Notes       string
+	Tags        []string
+}
+
+// Hit 检索命中的短链及相关度得分
+type Hit struct {
+	ID    uint
+	Score float64
+}
+
+// Searcher 短链检索接口，按相关度从高到低返回最多 limit 条结果
+type Searcher interface {
+	Search(ctx context.Context, query string, limit int) ([]Hit, error)
+}
+
+// Tokenize 将查询拆分为小写关键词：按空白切分，去掉 MySQL 布尔模式的运算符，去重
+func Tokenize(query string) []string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`"+-<>()~*@`, r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	seen := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		term := strings.ToLower(field)
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+		terms = append(terms, term)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// BooleanQuery 将关键词转换为 MySQL 布尔模式的查询串，每个关键词作为短语匹配，任一命中即可
+func BooleanQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + term + `"`
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,99 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize(`  Spring +"Sale" -2024  sale (promo)* `)
+	want := []string{"spring", "sale", "2024", "promo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %v, want %v", got, want)
+	}
+
+	if got := BooleanQuery([]string{"spring", "sale"}); got != `"spring" "sale"` {
+		t.Errorf("BooleanQuery() = %q", got)
+	}
+}
+
+func TestMemorySearcher(t *testing.T) {
+	searcher := NewMemorySearcher(
+		Document{ID: 1, ShortCode: "spring", TargetURL: "https://example.com/landing"},
+		Document{ID: 2, ShortCode: "abc", TargetURL: "https://example.com/spring-sale", Notes: "春季促销"},
+		Document{ID: 3, ShortCode: "xyz", TargetURL: "https://example.com/other", Tags: []string{"Spring"}},
+		Document{ID: 4, ShortCode: "none", TargetURL: "https://example.com/none"},
+	)
+
+	hits, err := searcher.Search(context.Background(), "SPRING", 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	var ids []uint
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	// 短码权重最高，其次是标签，目标地址最低
+	if want := []uint{1, 3, 2}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Search() ids = %v, want %v", ids, want)
+	}
+
+	hits, _ = searcher.Search(context.Background(), "促销", 10)
+	if len(hits) != 1 || hits[0].ID != 2 {
+		t.Errorf("Search() for notes = %v, want only id 2", hits)
+	}
+
+	searcher.Remove(1)
+	hits, _ = searcher.Search(context.Background(), "spring", 1)
+	if len(hits) != 1 || hits[0].ID != 3 {
+		t.Errorf("Search() after Remove with limit 1 = %v, want only id 3", hits)
+	}
+
+	if hits, _ := searcher.Search(context.Background(), "  ", 10); len(hits) != 0 {
+		t.Errorf("Search() with empty query = %v, want none", hits)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  []string
+	}{
+		{
+			name:  "case insensitive and escaped",
+			text:  "<b>Spring</b> & spring",
+			terms: []string{"spring"},
+			want:  []string{"&lt;b&gt;<mark>Spring</mark>&lt;/b&gt; &amp; <mark>spring</mark>"},
+		},
+		{
+			name:  "adjacent terms merged",
+			text:  "springsale",
+			terms: []string{"spring", "sale"},
+			want:  []string{"<mark>springsale</mark>"},
+		},
+		{
+			name:  "long text truncated",
+			text:  "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 春季促销 bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+			terms: []string{"促销"},
+			want:  []string{"…aaaaaaaaaaaaaaaaaaaaaaaaaaa 春季<mark>促销</mark> bbbbbbbbbbbbbbbbbbbbbbbbbbbbb…"},
+		},
+		{
+			name:  "no match",
+			text:  "nothing here",
+			terms: []string{"spring"},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, tt.terms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"net/http"
+	"shortlink-go/constant"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// ListShortLinkAliases 查询短链的所有别名
+func ListShortLinkAliases(ctx context.Context, id uint) ([]model.ShortLinkAlias, error) {
+	if _, err := GetShortLink(ctx, id); err != nil {
+		return nil, err
+	}
+
+	aliases := make([]model.ShortLinkAlias, 0)
+	if err := repository.DB.
+		Where("short_link_id = ?", id).
+		Order("id ASC").
+		Find(&aliases).Error; err != nil {
+		logging.Logger.Error("查询短链别名失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	return aliases, nil
+}
+
+// CreateShortLinkAlias 为短链添加别名，别名与短码共用同一命名空间
+func CreateShortLinkAlias(ctx context.Context, id uint, code string) (*model.ShortLinkAlias, error) {
+	if err := utils.ValidateShortCode(code); err != nil {
+		message := i18n.T(ctx, err.Error(), nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	if _, err := GetShortLink(ctx, id); err != nil {
+		return nil, err
+	}
+
+	alias := &model.ShortLinkAlias{
+		ShortLinkID: id,
+		Code:        code,
+	}
+	if err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		taken, err := isShortCodeTaken(tx, code, 0)
+		if err != nil {
+			return err
+		}
+		if taken {
+			message := i18n.T(ctx, "error.shortcode_exists", nil)
+			return apperrors.BusinessError(http.StatusConflict, message)
+		}
+		return tx.Create(alias).Error
+	}); err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		logging.Logger.Error("创建短链别名失败",
+			zap.Uint("id", id),
+			zap.String("alias", code),
+			zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	// 该短码之前可能缓存过空值（防穿透）
+	cleanupAliasRedisKeys(code, false)
+
+	return alias, nil
+}
+
+// DeleteShortLinkAlias 删除短链别名，同时清理别名的缓存与统计
+func DeleteShortLinkAlias(ctx context.Context, id uint, aliasID uint) error {
+	var alias model.ShortLinkAlias
+	if err := repository.DB.
+		Where("id = ? AND short_link_id = ?", aliasID, id).
+		First(&alias).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			message := i18n.T(ctx, "error.alias_not_found", nil)
+			return apperrors.BusinessError(http.StatusNotFound, message)
+		}
+		logging.Logger.Error("查询短链别名失败", zap.Uint("alias_id", aliasID), zap.Error(err))
+		return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	if err := repository.DB.Delete(&alias).Error; err != nil {
+		logging.Logger.Error("删除短链别名失败", zap.Uint("alias_id", aliasID), zap.Error(err))
+		return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	cleanupAliasRedisKeys(alias.Code, true)
+	return nil
+}
+
+// cleanupAliasRedisKeys 删除别名的跳转缓存，withStats 为 true 时同时删除别名维度的统计
+func cleanupAliasRedisKeys(code string, withStats bool) {
+	conn := repository.RedisPool.Get()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.Logger.Error("关闭 Redis 连接失败",
+				zap.Error(err),
+				zap.String("operation", "close"),
+				zap.String("connection_type", "redis"),
+			)
+		}
+	}()
+
+	keys := []string{constant.GetShortCodeKey(code)}
+	if withStats {
+		keys = append(keys, constant.GetAliasPVKey(code), constant.GetAliasUVKey(code))
+	}
+	for _, key := range keys {
+		if _, err := conn.Do("DEL", key); err != nil {
+			logging.Logger.Warn("删除 Redis 缓存失败", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"net/http"
+	"shortlink-go/pkg/botdetect"
+	"shortlink-go/pkg/logging"
+	"sync"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var (
+	botClassifier     *botdetect.Classifier
+	botClassifierOnce sync.Once
+)
+
+// getBotClassifier 首次使用时加载机器人列表：配置了 stats.bot_list_file 时从文件加载，否则或加载失败时使用内置列表
+func getBotClassifier() *botdetect.Classifier {
+	botClassifierOnce.Do(func() {
+		path := viper.GetString("stats.bot_list_file")
+		if path != "" {
+			classifier, err := botdetect.LoadFile(path)
+			if err == nil {
+				botClassifier = classifier
+				logging.Logger.Info("已加载机器人 User-Agent 列表", zap.String("path", path))
+				return
+			}
+			logging.Logger.Error("加载机器人 User-Agent 列表失败，使用内置列表", zap.String("path", path), zap.Error(err))
+		}
+		botClassifier = botdetect.New(botdetect.DefaultPatterns)
+	})
+	return botClassifier
+}
+
+// ClassifyBot 判定跳转请求是否为机器流量，返回空字符串表示正常访问
+func ClassifyBot(r *http.Request) botdetect.Reason {
+	return getBotClassifier().Classify(r)
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 批量任务保留策略
+const (
+	bulkJobMaxErrors = 100            // 每个任务最多记录的失败条数
+	bulkJobRetention = 24 * time.Hour // 已完成任务在内存中的保留时间
+)
+
+// bulkJob 后台批量任务（进度只保存在当前进程内存中）
+type bulkJob struct {
+	mu     sync.Mutex
+	status dto.BulkJobStatus
+}
+
+var (
+	bulkJobsMu sync.Mutex
+	bulkJobs   = make(map[string]*bulkJob)
+)
+
+// StartBulkShortLinkJob 校验请求、确定目标短链并启动后台任务，立即返回任务初始状态
+func StartBulkShortLinkJob(ctx context.Context, req dto.BulkShortLinkRequest) (*dto.BulkJobStatus, error) {
+	if err := validateBulkRequest(req); err != nil {
+		message := i18n.T(ctx, err.Error(), nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	ids := req.IDs
+	if req.Filter != nil {
+		ids = nil
+		if err := applyShortLinkFilters(repository.DB.Model(&model.ShortLink{}), *req.Filter).
+			Order("short_links.id ASC").
+			Pluck("short_links.id", &ids).Error; err != nil {
+			logging.Logger.Error("批量操作查询短链失败", zap.Error(err))
+			return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+		}
+	}
+
+	jobID, err := newBulkJobID()
+	if err != nil {
+		logging.Logger.Error("生成批量任务 ID 失败", zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	job := &bulkJob{status: dto.BulkJobStatus{
+		ID:        jobID,
+		Action:    req.Action,
+		Status:    dto.BulkJobPending,
+		Total:     len(ids),
+		Errors:    []dto.BulkJobError{},
+		CreatedAt: time.Now(),
+	}}
+
+	bulkJobsMu.Lock()
+	purgeFinishedBulkJobs()
+	bulkJobs[jobID] = job
+	bulkJobsMu.Unlock()
+
+	// 请求结束后 ctx 会被取消，但任务仍需使用其中的 i18n Localizer
+	go runBulkJob(context.WithoutCancel(ctx), job, req, ids, applyBulkAction)
+
+	status := job.snapshot()
+	return &status, nil
+}
+
+// GetBulkJobStatus 查询批量任务进度
+func GetBulkJobStatus(ctx context.Context, jobID string) (*dto.BulkJobStatus, error) {
+	bulkJobsMu.Lock()
+	job, ok := bulkJobs[jobID]
+	bulkJobsMu.Unlock()
+	if !ok {
+		message := i18n.T(ctx, "error.bulk_job_not_found", nil)
+		return nil, apperrors.BusinessError(http.StatusNotFound, message)
+	}
+
+	status := job.snapshot()
+	return &status, nil
+}
+
+// validateBulkRequest 在启动任务前校验参数，返回 i18n key
+func validateBulkRequest(req dto.BulkShortLinkRequest) error {
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return fmt.Errorf("error.bulk_target_required")
+	}
+	if req.Filter != nil {
+		// 空筛选条件会选中全部短链，批量操作必须显式给出至少一个条件
+		if !req.Filter.HasCriteria() {
+			return fmt.Errorf("error.bulk_filter_empty")
+		}
+		if err := req.Filter.Validate(); err != nil {
+			return err
+		}
+	}
+
+	switch req.Action {
+	case dto.BulkActionTag:
+		if len(req.Tags) == 0 {
+			return fmt.Errorf("error.tag_name_required")
+		}
+		for _, tag := range req.Tags {
+			if err := utils.ValidateTagName(strings.TrimSpace(tag)); err != nil {
+				return err
+			}
+		}
+	case dto.BulkActionRetarget:
+		if err := utils.ValidateTargetURL(req.TargetURL); err != nil {
+			return err
+		}
+		if req.RedirectCode != 0 {
+			if err := utils.ValidateRedirectCode(req.RedirectCode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runBulkJob 逐条处理短链，复用单条操作的全部副作用（统计同步、HLL 备份、缓存清理、历史版本）
+// apply 为单条短链的处理函数，正常运行时为 applyBulkAction
+func runBulkJob(ctx context.Context, job *bulkJob, req dto.BulkShortLinkRequest, ids []uint,
+	apply func(ctx context.Context, id uint, req dto.BulkShortLinkRequest) error) {
+	job.update(func(s *dto.BulkJobStatus) { s.Status = dto.BulkJobRunning })
+	logging.Logger.Info("批量任务开始",
+		zap.String("job_id", job.status.ID),
+		zap.String("action", req.Action),
+		zap.Int("total", len(ids)))
+
+	for _, id := range ids {
+		err := apply(ctx, id, req)
+		job.update(func(s *dto.BulkJobStatus) {
+			s.Processed++
+			if err == nil {
+				s.Succeeded++
+				return
+			}
+			s.Failed++
+			if len(s.Errors) < bulkJobMaxErrors {
+				s.Errors = append(s.Errors, dto.BulkJobError{ID: id, Message: err.Error()})
+			}
+		})
+	}
+
+	job.update(func(s *dto.BulkJobStatus) {
+		now := time.Now()
+		s.Status = dto.BulkJobCompleted
+		s.FinishedAt = &now
+	})
+	logging.Logger.Info("批量任务结束",
+		zap.String("job_id", job.status.ID),
+		zap.Int("succeeded", job.status.Succeeded),
+		zap.Int("failed", job.status.Failed))
+}
+
+// applyBulkAction 对单条短链执行批量操作
+func applyBulkAction(ctx context.Context, id uint, req dto.BulkShortLinkRequest) error {
+	switch req.Action {
+	case dto.BulkActionDelete:
+		return DeleteShortLink(ctx, id)
+
+	case dto.BulkActionDisable, dto.BulkActionEnable:
+		patch := dto.PatchShortLinkRequest{}
+		patch.Disabled = dto.PatchField[bool]{Set: true, Value: req.Action == dto.BulkActionDisable}
+		_, err := PatchShortLink(ctx, id, patch, 0)
+		return err
+
+	case dto.BulkActionRetarget:
+		patch := dto.PatchShortLinkRequest{}
+		patch.TargetURL = dto.PatchField[string]{Set: true, Value: req.TargetURL}
+		if req.RedirectCode != 0 {
+			patch.RedirectCode = dto.PatchField[int]{Set: true, Value: req.RedirectCode}
+		}
+		_, err := PatchShortLink(ctx, id, patch, 0)
+		return err
+
+	case dto.BulkActionTag:
+		existing, err := GetShortLink(ctx, id)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(existing.Tags)+len(req.Tags))
+		for _, tag := range existing.Tags {
+			names = append(names, tag.Name)
+		}
+		names = append(names, req.Tags...)
+
+		patch := dto.PatchShortLinkRequest{}
+		patch.Tags = dto.PatchField[[]string]{Set: true, Value: names}
+		_, err = PatchShortLink(ctx, id, patch, existing.Version)
+		return err
+
+	default:
+		return fmt.Errorf("unsupported bulk action: %s", req.Action)
+	}
+}
+
+// purgeFinishedBulkJobs 清理超过保留时间的已完成任务（调用方需持有 bulkJobsMu）
+func purgeFinishedBulkJobs() {
+	for id, job := range bulkJobs {
+		status := job.snapshot()
+		if status.FinishedAt != nil && time.Since(*status.FinishedAt) > bulkJobRetention {
+			delete(bulkJobs, id)
+		}
+	}
+}
+
+func newBulkJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (j *bulkJob) update(fn func(s *dto.BulkJobStatus)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.status)
+}
+
+// snapshot 返回任务状态的副本，避免并发读写
+func (j *bulkJob) snapshot() dto.BulkJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	status.Errors = append([]dto.BulkJobError(nil), j.status.Errors...)
+	return status
+}
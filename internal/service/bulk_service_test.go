@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/pkg/logging"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestValidateBulkRequestSelection(t *testing.T) {
+	disabled := true
+	tests := []struct {
+		name    string
+		req     dto.BulkShortLinkRequest
+		wantErr string
+	}{
+		{"ids", dto.BulkShortLinkRequest{IDs: []uint{1, 2}, Action: dto.BulkActionDisable}, ""},
+		{"filter with criteria", dto.BulkShortLinkRequest{Filter: &dto.ShortLinkQuery{Disabled: &disabled}, Action: dto.BulkActionEnable}, ""},
+		{"filter with tags", dto.BulkShortLinkRequest{Filter: &dto.ShortLinkQuery{Tags: []string{"promo"}}, Action: dto.BulkActionDelete}, ""},
+		{"neither ids nor filter", dto.BulkShortLinkRequest{Action: dto.BulkActionDelete}, "error.bulk_target_required"},
+		{"both ids and filter", dto.BulkShortLinkRequest{IDs: []uint{1}, Filter: &dto.ShortLinkQuery{ShortCode: "a"}, Action: dto.BulkActionDelete}, "error.bulk_target_required"},
+		{"empty filter", dto.BulkShortLinkRequest{Filter: &dto.ShortLinkQuery{}, Action: dto.BulkActionDelete}, "error.bulk_filter_empty"},
+		{"filter with only sorting", dto.BulkShortLinkRequest{Filter: &dto.ShortLinkQuery{SortBy: "id", SortOrder: dto.SortOrderAsc, ShortCodeMatch: dto.ShortCodeMatchExact}, Action: dto.BulkActionDelete}, "error.bulk_filter_empty"},
+		{"invalid filter", dto.BulkShortLinkRequest{Filter: &dto.ShortLinkQuery{ShortCode: "a", TagMode: "xor"}, Action: dto.BulkActionDelete}, "error.tag_mode_invalid"},
+		{"retarget to javascript", dto.BulkShortLinkRequest{IDs: []uint{1}, Action: dto.BulkActionRetarget, TargetURL: "javascript://x/%0aalert(1)"}, "error.target_url_scheme_invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBulkRequest(tt.req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateBulkRequest() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateBulkRequest() = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunBulkJobProgress(t *testing.T) {
+	logging.Logger = zap.NewNop()
+
+	ids := make([]uint, 2*bulkJobMaxErrors+10)
+	for i := range ids {
+		ids[i] = uint(i + 1)
+	}
+	job := &bulkJob{status: dto.BulkJobStatus{ID: "test", Status: dto.BulkJobPending, Total: len(ids)}}
+
+	// 偶数 ID 处理失败
+	var visited []uint
+	apply := func(_ context.Context, id uint, _ dto.BulkShortLinkRequest) error {
+		visited = append(visited, id)
+		if id%2 == 0 {
+			return errors.New("failed")
+		}
+		return nil
+	}
+	runBulkJob(context.Background(), job, dto.BulkShortLinkRequest{Action: dto.BulkActionDisable}, ids, apply)
+
+	status := job.snapshot()
+	if len(visited) != len(ids) {
+		t.Fatalf("visited %d links, want %d", len(visited), len(ids))
+	}
+	if status.Status != dto.BulkJobCompleted || status.FinishedAt == nil {
+		t.Errorf("status = %s (finishedAt %v), want completed", status.Status, status.FinishedAt)
+	}
+	if status.Processed != len(ids) || status.Succeeded+status.Failed != len(ids) {
+		t.Errorf("processed = %d, succeeded = %d, failed = %d, want %d in total",
+			status.Processed, status.Succeeded, status.Failed, len(ids))
+	}
+	if status.Failed != len(ids)/2 {
+		t.Errorf("failed = %d, want %d", status.Failed, len(ids)/2)
+	}
+	if len(status.Errors) != bulkJobMaxErrors {
+		t.Errorf("errors = %d, want capped at %d", len(status.Errors), bulkJobMaxErrors)
+	}
+	if status.Errors[0].ID != 2 {
+		t.Errorf("first error id = %d, want 2", status.Errors[0].ID)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/utils"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultTrackingParams 未配置 shortlink.tracking_params 时，规范化目标地址时移除的跟踪参数
+var defaultTrackingParams = []string{"utm_*", "gclid", "fbclid", "msclkid", "yclid", "mc_cid", "mc_eid", "spm"}
+
+// maxDuplicateShortLinks 创建时最多返回的重复短链数量
+const maxDuplicateShortLinks = 10
+
+func trackingParams() []string {
+	if viper.IsSet("shortlink.tracking_params") {
+		return viper.GetStringSlice("shortlink.tracking_params")
+	}
+	return defaultTrackingParams
+}
+
+// canonicalTargetHash 计算规范化后目标地址的 SHA-256，无法解析时按原始地址计算
+func canonicalTargetHash(targetURL string) string {
+	canonical, err := utils.CanonicalizeURL(targetURL, trackingParams())
+	if err != nil {
+		canonical = targetURL
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
+// findDuplicateShortLinks 查询规范化目标地址相同的短链（不含回收站），按创建顺序返回
+func findDuplicateShortLinks(canonicalHash string) ([]model.ShortLink, error) {
+	links := make([]model.ShortLink, 0)
+	err := repository.DB.
+		Where("canonical_hash = ?", canonicalHash).
+		Order("id ASC").
+		Limit(maxDuplicateShortLinks).
+		Find(&links).Error
+	return links, err
+}
+
+// BackfillCanonicalHashes 为升级前创建的短链补全 canonical_hash（启动时在后台执行）
+func BackfillCanonicalHashes() {
+	var links []model.ShortLink
+	filled := 0
+	result := repository.DB.Unscoped().
+		Select("id", "target_url").
+		Where("canonical_hash = '' OR canonical_hash IS NULL").
+		FindInBatches(&links, 500, func(tx *gorm.DB, _ int) error {
+			for _, link := range links {
+				if err := repository.DB.Unscoped().Model(&model.ShortLink{}).
+					Where("id = ?", link.ID).
+					UpdateColumn("canonical_hash", canonicalTargetHash(link.TargetURL)).Error; err != nil {
+					return err
+				}
+				filled++
+			}
+			return nil
+		})
+	if result.Error != nil {
+		logging.Logger.Error("补全 canonical_hash 失败", zap.Int("filled", filled), zap.Error(result.Error))
+		return
+	}
+	if filled > 0 {
+		logging.Logger.Info("补全 canonical_hash 完成", zap.Int("filled", filled))
+	}
+}
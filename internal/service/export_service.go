@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/xlsx"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 导出文件格式
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatXLSX   = "xlsx"
+)
+
+// exportFlushInterval 每写出多少行刷新一次输出缓冲
+const exportFlushInterval = 1000
+
+// exportRow 导出的一行数据
+type exportRow struct {
+	ID           uint           `json:"id"`
+	ShortCode    string         `json:"shortCode"`
+	TargetURL    string         `json:"targetUrl"`
+	RedirectCode int            `json:"redirectCode"`
+	Disabled     bool           `json:"disabled"`
+	ExpiresAt    *time.Time     `json:"expiresAt"`
+	Tags         sql.NullString `json:"-"` // GROUP_CONCAT 结果，; 分隔
+	Description  string         `json:"description"`
+	Notes        string         `json:"notes"`
+	TotalPV      uint64         `json:"totalPv"`
+	TotalUV      uint64         `json:"totalUv"`
+	RangePV      uint64         `json:"rangePv,omitempty"`
+	RangeUV      uint64         `json:"rangeUv,omitempty"` // 各日 UV 之和（跨天访客会重复计数）
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+}
+
+// exportRowWriter 不同导出格式的行写入器
+type exportRowWriter interface {
+	WriteHeader(withStats bool) error
+	WriteRow(row *exportRow, withStats bool) error
+	Flush() error
+	Close() error
+}
+
+// ValidateExportFormat 校验导出格式，返回对应的 Content-Type
+func ValidateExportFormat(ctx context.Context, format string) (string, error) {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8", nil
+	case ExportFormatNDJSON:
+		return "application/x-ndjson", nil
+	case ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", nil
+	default:
+		message := i18n.T(ctx, "error.export_format_invalid", nil)
+		return "", apperrors.InvalidRequestError(message)
+	}
+}
+
+// ExportShortLinks 按列表筛选条件逐行流式导出短链，statsFrom/statsTo 任一不为空时关联该日期范围内的 daily_stats
+func ExportShortLinks(ctx context.Context, w io.Writer, format string, query dto.ShortLinkQuery, statsFrom, statsTo string) error {
+	if _, err := ValidateExportFormat(ctx, format); err != nil {
+		return err
+	}
+
+	withStats := statsFrom != "" || statsTo != ""
+	var fromDate, toDate string
+	if withStats {
+		var err error
+		if fromDate, toDate, err = parseStatsDateRange(statsFrom, statsTo); err != nil {
+			message := i18n.T(ctx, "error.date_range_invalid", nil)
+			return apperrors.InvalidRequestError(message)
+		}
+	}
+
+	selects := []string{
+		"short_links.id", "short_links.short_code", "short_links.target_url", "short_links.redirect_code",
+		"short_links.disabled", "short_links.expires_at", "short_links.description", "short_links.notes",
+		"short_links.total_pv", "short_links.total_uv", "short_links.created_at", "short_links.updated_at",
+		"(SELECT GROUP_CONCAT(tags.name ORDER BY tags.name SEPARATOR ';') FROM short_link_tags " +
+			"JOIN tags ON tags.id = short_link_tags.tag_id " +
+			"WHERE short_link_tags.short_link_id = short_links.id) AS tags",
+	}
+
+	db := repository.DB.Model(&model.ShortLink{})
+	if withStats {
+		selects = append(selects, "COALESCE(ds.range_pv, 0) AS range_pv", "COALESCE(ds.range_uv, 0) AS range_uv")
+		db = db.Joins("LEFT JOIN (SELECT short_link_id, SUM(pv) AS range_pv, SUM(uv) AS range_uv "+
+			"FROM daily_stats WHERE date BETWEEN ? AND ? GROUP BY short_link_id) ds "+
+			"ON ds.short_link_id = short_links.id", fromDate, toDate)
+	}
+	db = applyShortLinkFilters(db.Select(strings.Join(selects, ", ")), query).Order("short_links.id ASC")
+
+	rows, err := db.Rows()
+	if err != nil {
+		logging.Logger.Error("导出短链查询失败", zap.Error(err))
+		return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logging.Logger.Warn("关闭导出查询结果失败", zap.Error(err))
+		}
+	}()
+
+	writer, err := newExportRowWriter(format, w)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteHeader(withStats); err != nil {
+		return err
+	}
+
+	count := 0
+	for rows.Next() {
+		var row exportRow
+		if err := repository.DB.ScanRows(rows, &row); err != nil {
+			logging.Logger.Error("导出短链读取数据失败", zap.Int("written", count), zap.Error(err))
+			return err
+		}
+		if err := writer.WriteRow(&row, withStats); err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logging.Logger.Error("导出短链遍历数据失败", zap.Int("written", count), zap.Error(err))
+		return err
+	}
+
+	logging.Logger.Info("导出短链完成", zap.String("format", format), zap.Int("rows", count))
+	return writer.Close()
+}
+
+func newExportRowWriter(format string, w io.Writer) (exportRowWriter, error) {
+	switch format {
+	case ExportFormatNDJSON:
+		return &ndjsonExportWriter{w: w, encoder: json.NewEncoder(w)}, nil
+	case ExportFormatXLSX:
+		sw, err := xlsx.NewStreamWriter(w, "shortlinks")
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxExportWriter{w: w, sw: sw}, nil
+	default:
+		return &csvExportWriter{w: w, cw: csv.NewWriter(w)}, nil
+	}
+}
+
+// exportHeader 表格类格式的表头
+func exportHeader(withStats bool) []string {
+	header := []string{"id", "shortCode", "targetUrl", "redirectCode", "disabled", "expiresAt",
+		"tags", "description", "notes", "totalPv", "totalUv", "createdAt", "updatedAt"}
+	if withStats {
+		header = append(header, "rangePv", "rangeUv")
+	}
+	return header
+}
+
+// cells 表格类格式的一行
+func (r *exportRow) cells(withStats bool) []interface{} {
+	expiresAt := ""
+	if r.ExpiresAt != nil {
+		expiresAt = r.ExpiresAt.Format(time.RFC3339)
+	}
+	cells := []interface{}{r.ID, r.ShortCode, r.TargetURL, r.RedirectCode, r.Disabled, expiresAt,
+		r.Tags.String, r.Description, r.Notes, r.TotalPV, r.TotalUV,
+		r.CreatedAt.Format(time.RFC3339), r.UpdatedAt.Format(time.RFC3339)}
+	if withStats {
+		cells = append(cells, r.RangePV, r.RangeUV)
+	}
+	return cells
+}
+
+// flushHTTP 刷新底层的 HTTP 响应（如果支持）
+func flushHTTP(w io.Writer) {
+	if flusher, ok := w.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+}
+
+type csvExportWriter struct {
+	w  io.Writer
+	cw *csv.Writer
+}
+
+func (c *csvExportWriter) WriteHeader(withStats bool) error {
+	return c.cw.Write(exportHeader(withStats))
+}
+
+func (c *csvExportWriter) WriteRow(row *exportRow, withStats bool) error {
+	cells := row.cells(withStats)
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case string:
+			record[i] = escapeCSVFormula(v)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		case int:
+			record[i] = strconv.Itoa(v)
+		case uint:
+			record[i] = strconv.FormatUint(uint64(v), 10)
+		case uint64:
+			record[i] = strconv.FormatUint(v, 10)
+		}
+	}
+	return c.cw.Write(record)
+}
+
+// escapeCSVFormula 在可能被表格软件当作公式执行的单元格前加单引号，防止 CSV 公式注入
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.cw.Flush()
+	flushHTTP(c.w)
+	return c.cw.Error()
+}
+
+func (c *csvExportWriter) Close() error {
+	return c.Flush()
+}
+
+type ndjsonExportWriter struct {
+	w       io.Writer
+	encoder *json.Encoder
+}
+
+func (n *ndjsonExportWriter) WriteHeader(bool) error {
+	return nil
+}
+
+func (n *ndjsonExportWriter) WriteRow(row *exportRow, _ bool) error {
+	tags := make([]string, 0)
+	if row.Tags.String != "" {
+		tags = strings.Split(row.Tags.String, ";")
+	}
+	return n.encoder.Encode(struct {
+		*exportRow
+		Tags []string `json:"tags"`
+	}{row, tags})
+}
+
+func (n *ndjsonExportWriter) Flush() error {
+	flushHTTP(n.w)
+	return nil
+}
+
+func (n *ndjsonExportWriter) Close() error {
+	return n.Flush()
+}
+
+type xlsxExportWriter struct {
+	w  io.Writer
+	sw *xlsx.StreamWriter
+}
+
+func (x *xlsxExportWriter) WriteHeader(withStats bool) error {
+	header := exportHeader(withStats)
+	cells := make([]interface{}, len(header))
+	for i, name := range header {
+		cells[i] = name
+	}
+	return x.sw.WriteRow(cells...)
+}
+
+func (x *xlsxExportWriter) WriteRow(row *exportRow, withStats bool) error {
+	return x.sw.WriteRow(row.cells(withStats)...)
+}
+
+func (x *xlsxExportWriter) Flush() error {
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	flushHTTP(x.w)
+	return nil
+}
+
+func (x *xlsxExportWriter) Close() error {
+	if err := x.sw.Close(); err != nil {
+		return err
+	}
+	flushHTTP(x.w)
+	return nil
+}
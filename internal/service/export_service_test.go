@@ -0,0 +1,166 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportTestRow() *exportRow {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &exportRow{
+		ID:           7,
+		ShortCode:    "promo/spring",
+		TargetURL:    "https://shop.example.com/?a=1&b=2",
+		RedirectCode: 302,
+		Tags:         sql.NullString{String: "sale;ads", Valid: true},
+		Description:  `Spring "sale", <50%>`,
+		TotalPV:      12,
+		TotalUV:      5,
+		RangePV:      3,
+		RangeUV:      2,
+		CreatedAt:    created,
+		UpdatedAt:    created,
+	}
+}
+
+// writeExport 用指定格式写出表头与一行数据
+func writeExport(t *testing.T, format string, withStats bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := newExportRowWriter(format, &buf)
+	if err != nil {
+		t.Fatalf("newExportRowWriter(%s) error = %v", format, err)
+	}
+	if err := writer.WriteHeader(withStats); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteRow(exportTestRow(), withStats); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExportCSV(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(writeExport(t, ExportFormatCSV, true))).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("records = %d, want 2", len(records))
+	}
+	header, row := records[0], records[1]
+	if len(header) != len(row) || len(header) != len(exportHeader(true)) {
+		t.Fatalf("header has %d columns, row has %d", len(header), len(row))
+	}
+	want := map[string]string{
+		"id":           "7",
+		"shortCode":    "promo/spring",
+		"targetUrl":    "https://shop.example.com/?a=1&b=2",
+		"redirectCode": "302",
+		"disabled":     "false",
+		"expiresAt":    "",
+		"tags":         "sale;ads",
+		"description":  `Spring "sale", <50%>`,
+		"totalPv":      "12",
+		"createdAt":    "2026-01-02T03:04:05Z",
+		"rangePv":      "3",
+		"rangeUv":      "2",
+	}
+	for i, name := range header {
+		if value, ok := want[name]; ok && row[i] != value {
+			t.Errorf("%s = %q, want %q", name, row[i], value)
+		}
+	}
+}
+
+func TestExportCSVEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := newExportRowWriter(ExportFormatCSV, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := exportTestRow()
+	row.Description = "=cmd|' /C calc'!A0"
+	row.Tags = sql.NullString{String: "@sum;-1", Valid: true}
+	if err := writer.WriteRow(row, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := csv.NewReader(&buf).Read()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	want := map[string]string{
+		"shortCode":   "promo/spring",
+		"tags":        "'@sum;-1",
+		"description": "'=cmd|' /C calc'!A0",
+	}
+	for i, name := range exportHeader(false) {
+		if value, ok := want[name]; ok && record[i] != value {
+			t.Errorf("%s = %q, want %q", name, record[i], value)
+		}
+	}
+}
+
+func TestExportNDJSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(writeExport(t, ExportFormatNDJSON, false))), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("lines = %d, want 1 (no header)", len(lines))
+	}
+	var got struct {
+		ID   uint     `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	if got.ID != 7 || strings.Join(got.Tags, ",") != "sale,ads" {
+		t.Errorf("got %+v, want id 7 and tags [sale ads]", got)
+	}
+}
+
+func TestExportXLSX(t *testing.T) {
+	data := writeExport(t, ExportFormatXLSX, false)
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("output is not a zip archive: %v", err)
+	}
+	f, err := zr.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sheet, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fragment := range []string{
+		`<row r="1">`,
+		`<row r="2">`,
+		`<c r="A2"><v>7</v></c>`,
+		`<c r="D2"><v>302</v></c>`,
+		`Spring &#34;sale&#34;, &lt;50%&gt;`,
+		`https://shop.example.com/?a=1&amp;b=2`,
+	} {
+		if !bytes.Contains(sheet, []byte(fragment)) {
+			t.Errorf("sheet1.xml does not contain %s", fragment)
+		}
+	}
+	if bytes.Contains(sheet, []byte(`<row r="3">`)) {
+		t.Error("sheet1.xml contains an unexpected third row")
+	}
+}
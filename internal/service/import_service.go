@@ -0,0 +1,567 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"shortlink-go/constant"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 导入文件格式
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// importBatchSize 每个事务批量写入的行数
+const importBatchSize = 500
+
+// parsedImportRow 解析后的导入行，err 不为空表示该行格式错误（i18n key）
+type parsedImportRow struct {
+	row dto.ImportShortLinkRow
+	err error
+}
+
+// importItem 通过校验、等待写入的导入行
+type importItem struct {
+	result   *dto.ImportRowResult
+	row      dto.ImportShortLinkRow
+	existing *model.ShortLink // 覆盖模式下已存在的短链
+}
+
+// ImportShortLinks 批量导入短链：逐行校验，按冲突策略处理已存在的短码，分批在事务中写入并返回逐行报告
+func ImportShortLinks(ctx context.Context, format string, reader io.Reader, dryRun bool, onConflict string) (*dto.ImportReport, error) {
+	switch onConflict {
+	case "":
+		onConflict = dto.ImportConflictSkip
+	case dto.ImportConflictSkip, dto.ImportConflictOverwrite, dto.ImportConflictFail:
+	default:
+		message := i18n.T(ctx, "error.import_conflict_policy_invalid", nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	parsed, err := parseImportRows(format, reader)
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			return nil, apperrors.InvalidRequestError(i18n.T(ctx, appErr.Message, nil))
+		}
+		logging.Logger.Warn("解析导入文件失败", zap.String("format", format), zap.Error(err))
+		return nil, apperrors.InvalidRequestError(i18n.T(ctx, "error.request_body_invalid", nil))
+	}
+	if len(parsed) == 0 {
+		return nil, apperrors.InvalidRequestError(i18n.T(ctx, "error.import_empty", nil))
+	}
+
+	report := &dto.ImportReport{
+		DryRun:     dryRun,
+		OnConflict: onConflict,
+		Total:      len(parsed),
+		Rows:       make([]dto.ImportRowResult, len(parsed)),
+	}
+
+	// 1. 逐行校验
+	items := make([]*importItem, 0, len(parsed))
+	seen := make(map[string]struct{}, len(parsed))
+	for i, p := range parsed {
+		result := &report.Rows[i]
+		result.Row = i + 1
+		result.ShortCode = p.row.ShortCode
+
+		err := p.err
+		if err == nil {
+			err = validateImportRow(&p.row)
+		}
+		if err == nil {
+			if _, ok := seen[p.row.ShortCode]; ok {
+				err = fmt.Errorf("error.import_duplicate_code")
+			}
+		}
+		if err != nil {
+			result.Status = dto.ImportStatusInvalid
+			result.Message = i18n.T(ctx, err.Error(), nil)
+			continue
+		}
+
+		seen[p.row.ShortCode] = struct{}{}
+		items = append(items, &importItem{result: result, row: p.row})
+	}
+
+	// onConflict=fail 时任一行被拒绝（校验失败或短码冲突）都整体放弃，不写入任何数据
+	if onConflict == dto.ImportConflictFail && len(items) < len(parsed) {
+		return abortImport(ctx, report, items), nil
+	}
+
+	// 2. 按冲突策略处理已存在的短码
+	toCreate, toUpdate, conflicts, err := classifyImportItems(ctx, items, onConflict)
+	if err != nil {
+		logging.Logger.Error("导入时查询已有短码失败", zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	if onConflict == dto.ImportConflictFail && conflicts > 0 {
+		return abortImport(ctx, report, append(toCreate, toUpdate...)), nil
+	}
+
+	// 3. 试运行只返回预期结果，不写入
+	if dryRun {
+		for _, item := range toCreate {
+			item.result.Status = dto.ImportStatusCreated
+		}
+		for _, item := range toUpdate {
+			item.result.Status = dto.ImportStatusUpdated
+		}
+		return summarizeImportReport(report), nil
+	}
+
+	// 4. 分批写入新短链
+	tagCache := make(map[string]model.Tag)
+	for start := 0; start < len(toCreate); start += importBatchSize {
+		end := min(start+importBatchSize, len(toCreate))
+		createImportBatch(ctx, toCreate[start:end], tagCache)
+	}
+
+	// 5. 覆盖已存在的短链（复用更新流程：版本号、历史版本、缓存失效）
+	for _, item := range toUpdate {
+		overwriteImportedShortLink(ctx, item)
+	}
+
+	return summarizeImportReport(report), nil
+}
+
+// abortImport 放弃整个导入，其余可写入的行标记为 skipped
+func abortImport(ctx context.Context, report *dto.ImportReport, items []*importItem) *dto.ImportReport {
+	report.Aborted = true
+	for _, item := range items {
+		item.result.Status = dto.ImportStatusSkipped
+		item.result.Message = i18n.T(ctx, "error.import_aborted", nil)
+	}
+	return summarizeImportReport(report)
+}
+
+// parseImportRows 按格式解析导入文件
+func parseImportRows(format string, reader io.Reader) ([]parsedImportRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseImportCSV(reader)
+	case ImportFormatNDJSON:
+		return parseImportNDJSON(reader)
+	default:
+		return nil, apperrors.InvalidRequestError("error.import_format_invalid")
+	}
+}
+
+// parseImportCSV 解析 CSV：首行为表头，列名不区分大小写，tags 列使用 ; 分隔
+func parseImportCSV(reader io.Reader) ([]parsedImportRow, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if column := normalizeImportColumn(name); column != "" {
+			columns[column] = i
+		}
+	}
+	if _, ok := columns["code"]; !ok {
+		return nil, apperrors.InvalidRequestError("error.import_header_invalid")
+	}
+	if _, ok := columns["target"]; !ok {
+		return nil, apperrors.InvalidRequestError("error.import_header_invalid")
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	rows := make([]parsedImportRow, 0)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, parsedImportRow{err: fmt.Errorf("error.import_row_invalid")})
+				continue
+			}
+			return nil, err
+		}
+
+		var p parsedImportRow
+		p.row.ShortCode = field(record, "code")
+		p.row.TargetURL = field(record, "target")
+
+		if value := field(record, "redirect"); value != "" {
+			if p.row.RedirectCode, err = strconv.Atoi(value); err != nil {
+				p.err = fmt.Errorf("error.redirect_code_invalid")
+			}
+		}
+		if value := field(record, "disabled"); value != "" && p.err == nil {
+			if p.row.Disabled, err = strconv.ParseBool(value); err != nil {
+				p.err = fmt.Errorf("error.import_row_invalid")
+			}
+		}
+		if value := field(record, "expiry"); value != "" && p.err == nil {
+			expiresAt, err := parseImportTime(value)
+			if err != nil {
+				p.err = fmt.Errorf("error.expires_at_invalid")
+			}
+			p.row.ExpiresAt = expiresAt
+		}
+		if value := field(record, "tags"); value != "" {
+			p.row.Tags = strings.Split(value, ";")
+		}
+		rows = append(rows, p)
+	}
+	return rows, nil
+}
+
+// parseImportNDJSON 解析 NDJSON：每个非空行为一个 dto.ImportShortLinkRow
+func parseImportNDJSON(reader io.Reader) ([]parsedImportRow, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	rows := make([]parsedImportRow, 0)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var p parsedImportRow
+		if err := json.Unmarshal([]byte(line), &p.row); err != nil {
+			p.err = fmt.Errorf("error.import_row_invalid")
+		}
+		rows = append(rows, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// normalizeImportColumn 将 CSV 表头映射为内部列名，未知列返回空字符串
+func normalizeImportColumn(name string) string {
+	name = strings.TrimPrefix(name, "\ufeff") // Excel 导出的 UTF-8 BOM
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer("_", "", "-", "", " ", "").Replace(name)
+
+	switch name {
+	case "code", "shortcode":
+		return "code"
+	case "target", "targeturl", "url":
+		return "target"
+	case "redirect", "redirectcode":
+		return "redirect"
+	case "disabled":
+		return "disabled"
+	case "expiry", "expiresat", "expireat":
+		return "expiry"
+	case "tags":
+		return "tags"
+	default:
+		return ""
+	}
+}
+
+// parseImportTime 支持 RFC3339 与 "yyyy-MM-dd HH:mm:ss"（本地时区）两种格式
+func parseImportTime(value string) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// validateImportRow 复用接口的校验规则（迁移的历史数据允许过期时间早于当前时间）
+func validateImportRow(row *dto.ImportShortLinkRow) error {
+	if err := utils.ValidateShortCode(row.ShortCode); err != nil {
+		return err
+	}
+	if err := utils.ValidateTargetURL(row.TargetURL); err != nil {
+		return err
+	}
+
+	if row.RedirectCode == 0 {
+		row.RedirectCode = http.StatusFound
+	}
+	if err := utils.ValidateRedirectCode(row.RedirectCode); err != nil {
+		return err
+	}
+
+	for _, tag := range row.Tags {
+		if err := utils.ValidateTagName(strings.TrimSpace(tag)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// classifyImportItems 批量查询已存在的短码与别名，按冲突策略拆分为新建与覆盖两组，返回冲突行数
+func classifyImportItems(ctx context.Context, items []*importItem, onConflict string) (toCreate, toUpdate []*importItem, conflicts int, err error) {
+	for start := 0; start < len(items); start += importBatchSize {
+		batch := items[start:min(start+importBatchSize, len(items))]
+		codes := make([]string, 0, len(batch))
+		for _, item := range batch {
+			codes = append(codes, item.row.ShortCode)
+		}
+
+		var links []model.ShortLink
+		if err = repository.DB.Unscoped().Where("short_code IN ?", codes).Find(&links).Error; err != nil {
+			return
+		}
+		existing := make(map[string]*model.ShortLink, len(links))
+		for i := range links {
+			existing[links[i].ShortCode] = &links[i]
+		}
+
+		var aliasCodes []string
+		if err = repository.DB.Model(&model.ShortLinkAlias{}).
+			Where("code IN ?", codes).
+			Pluck("code", &aliasCodes).Error; err != nil {
+			return
+		}
+		aliases := make(map[string]struct{}, len(aliasCodes))
+		for _, code := range aliasCodes {
+			aliases[code] = struct{}{}
+		}
+
+		for _, item := range batch {
+			code := item.row.ShortCode
+			if _, ok := aliases[code]; ok {
+				// 别名无法被覆盖，任何策略下都视为冲突
+				item.result.Status = dto.ImportStatusConflict
+				item.result.Message = i18n.T(ctx, "error.import_alias_conflict", nil)
+				conflicts++
+				continue
+			}
+
+			link, ok := existing[code]
+			if !ok {
+				toCreate = append(toCreate, item)
+				continue
+			}
+			if link.DeletedAt.Valid {
+				// 回收站中的短链需要先恢复，任何策略下都视为冲突
+				item.result.Status = dto.ImportStatusConflict
+				item.result.Message = i18n.T(ctx, "error.shortcode_in_trash", nil)
+				conflicts++
+				continue
+			}
+
+			switch onConflict {
+			case dto.ImportConflictSkip:
+				item.result.Status = dto.ImportStatusSkipped
+				item.result.Message = i18n.T(ctx, "error.shortcode_exists", nil)
+			case dto.ImportConflictOverwrite:
+				item.existing = link
+				toUpdate = append(toUpdate, item)
+			default:
+				item.result.Status = dto.ImportStatusConflict
+				item.result.Message = i18n.T(ctx, "error.shortcode_exists", nil)
+				conflicts++
+			}
+		}
+	}
+	return
+}
+
+// createImportBatch 在一个事务中写入一批新短链及其第一个版本，失败时整批标记为 failed
+func createImportBatch(ctx context.Context, batch []*importItem, tagCache map[string]model.Tag) {
+	links := make([]model.ShortLink, len(batch))
+
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		for i, item := range batch {
+			tags, err := resolveTagsCached(tx, item.row.Tags, tagCache)
+			if err != nil {
+				return err
+			}
+			links[i] = model.ShortLink{
+				ShortCode:     item.row.ShortCode,
+				TargetURL:     item.row.TargetURL,
+				CanonicalHash: canonicalTargetHash(item.row.TargetURL),
+				RedirectCode:  item.row.RedirectCode,
+				Disabled:      item.row.Disabled,
+				ExpiresAt:     item.row.ExpiresAt,
+				Tags:          tags,
+			}
+		}
+
+		if err := tx.CreateInBatches(&links, importBatchSize).Error; err != nil {
+			return err
+		}
+
+		revisions := make([]model.ShortLinkRevision, len(links))
+		for i, link := range links {
+			revisions[i] = model.ShortLinkRevision{
+				ShortLinkID:  link.ID,
+				Revision:     1,
+				TargetURL:    link.TargetURL,
+				RedirectCode: link.RedirectCode,
+			}
+		}
+		return tx.CreateInBatches(&revisions, importBatchSize).Error
+	})
+	if err != nil {
+		logging.Logger.Error("批量导入短链失败",
+			zap.String("first_short_code", batch[0].row.ShortCode),
+			zap.Int("size", len(batch)),
+			zap.Error(err))
+		// 事务回滚后标签缓存可能包含未提交的标签
+		clear(tagCache)
+		for _, item := range batch {
+			item.result.Status = dto.ImportStatusFailed
+			item.result.Message = i18n.T(ctx, "error.system_error", nil)
+		}
+		return
+	}
+
+	for _, item := range batch {
+		item.result.Status = dto.ImportStatusCreated
+	}
+
+	// 新短码之前可能缓存过空值（防穿透）
+	cacheKeys := make([]interface{}, 0, len(links))
+	for _, link := range links {
+		cacheKeys = append(cacheKeys, constant.GetShortCodeKey(link.ShortCode))
+	}
+	conn := repository.RedisPool.Get()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.Logger.Error("关闭 Redis 连接失败",
+				zap.Error(err),
+				zap.String("operation", "close"),
+				zap.String("connection_type", "redis"),
+			)
+		}
+	}()
+	if _, err := conn.Do("DEL", cacheKeys...); err != nil {
+		logging.Logger.Warn("删除 Redis 缓存失败", zap.Int("keys", len(cacheKeys)), zap.Error(err))
+	}
+}
+
+// overwriteImportedShortLink 用导入行覆盖已存在的短链
+func overwriteImportedShortLink(ctx context.Context, item *importItem) {
+	existing := item.existing
+	row := item.row
+
+	updates := map[string]interface{}{
+		"expires_at": row.ExpiresAt,
+	}
+	existing.ExpiresAt = row.ExpiresAt
+
+	targetChanged := existing.TargetURL != row.TargetURL || existing.RedirectCode != row.RedirectCode
+	if targetChanged {
+		existing.TargetURL = row.TargetURL
+		existing.RedirectCode = row.RedirectCode
+		updates["target_url"] = row.TargetURL
+		updates["redirect_code"] = row.RedirectCode
+	}
+
+	replaceTags := importTagsReplacer(existing, row)
+	var err error
+	if row.Disabled != existing.Disabled {
+		err = saveShortLinkChangesWithStatus(ctx, existing, row.Disabled, existing.Version, updates, targetChanged, replaceTags)
+	} else {
+		err = saveShortLinkChanges(ctx, existing, existing.Version, updates, targetChanged, replaceTags)
+	}
+	if err != nil {
+		item.result.Status = dto.ImportStatusFailed
+		item.result.Message = err.Error()
+		return
+	}
+	item.result.Status = dto.ImportStatusUpdated
+}
+
+// importTagsReplacer 返回覆盖时替换标签的操作；行中没有提供标签（无 tags 列、值为空或 NDJSON 缺少 tags 字段）时返回 nil，保留原有标签
+func importTagsReplacer(existing *model.ShortLink, row dto.ImportShortLinkRow) func(tx *gorm.DB) error {
+	if row.Tags == nil {
+		return nil
+	}
+	return func(tx *gorm.DB) error {
+		return replaceShortLinkTags(tx, existing, row.Tags)
+	}
+}
+
+// resolveTagsCached 与 resolveTags 相同，但复用本次导入中已查询过的标签
+func resolveTagsCached(tx *gorm.DB, names []string, cache map[string]model.Tag) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	missing := make([]string, 0)
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if _, ok := seen[name]; ok || name == "" {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		if tag, ok := cache[name]; ok {
+			tags = append(tags, tag)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return tags, nil
+	}
+
+	resolved, err := resolveTags(tx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range resolved {
+		cache[tag.Name] = tag
+	}
+	return append(tags, resolved...), nil
+}
+
+// summarizeImportReport 汇总各状态的行数
+func summarizeImportReport(report *dto.ImportReport) *dto.ImportReport {
+	for _, row := range report.Rows {
+		switch row.Status {
+		case dto.ImportStatusCreated:
+			report.Created++
+		case dto.ImportStatusUpdated:
+			report.Updated++
+		case dto.ImportStatusSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+	}
+	return report
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"strings"
+	"testing"
+
+	thirdPartyI18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func TestImportFailPolicyAbortsOnInvalidRows(t *testing.T) {
+	bundle, err := i18n.InitI18n([]string{"../../i18n/en.toml", "../../i18n/zh.toml"}, "en")
+	if err != nil {
+		t.Fatalf("InitI18n() error = %v", err)
+	}
+	ctx := context.WithValue(context.Background(), "i18n.Localizer", thirdPartyI18n.NewLocalizer(bundle, "en"))
+
+	// 第二行目标地址不合法，整个导入在查询数据库之前就被放弃
+	csv := "shortCode,targetUrl\nvalid-code,https://example.com/a\nbad-code,javascript:alert(1)\n"
+	report, err := ImportShortLinks(ctx, ImportFormatCSV, strings.NewReader(csv), false, dto.ImportConflictFail)
+	if err != nil {
+		t.Fatalf("ImportShortLinks() error = %v", err)
+	}
+
+	if !report.Aborted {
+		t.Error("report should be aborted")
+	}
+	if report.Created != 0 || report.Updated != 0 {
+		t.Errorf("created = %d, updated = %d, want nothing written", report.Created, report.Updated)
+	}
+	if got := report.Rows[0].Status; got != dto.ImportStatusSkipped {
+		t.Errorf("valid row status = %s, want %s", got, dto.ImportStatusSkipped)
+	}
+	if got := report.Rows[1].Status; got != dto.ImportStatusInvalid {
+		t.Errorf("invalid row status = %s, want %s", got, dto.ImportStatusInvalid)
+	}
+}
+
+func TestImportOverwriteKeepsTagsWhenNotProvided(t *testing.T) {
+	existing := &model.ShortLink{ShortCode: "promo", Tags: []model.Tag{{Name: "sale"}}}
+
+	tests := []struct {
+		name        string
+		format      string
+		input       string
+		wantReplace bool
+	}{
+		{"csv without tags column", ImportFormatCSV, "shortCode,targetUrl\npromo,https://example.com/a\n", false},
+		{"csv with empty tags", ImportFormatCSV, "shortCode,targetUrl,tags\npromo,https://example.com/a,\n", false},
+		{"csv with tags", ImportFormatCSV, "shortCode,targetUrl,tags\npromo,https://example.com/a,ads\n", true},
+		{"ndjson without tags", ImportFormatNDJSON, `{"shortCode":"promo","targetUrl":"https://example.com/a"}` + "\n", false},
+		{"ndjson with empty tags", ImportFormatNDJSON, `{"shortCode":"promo","targetUrl":"https://example.com/a","tags":[]}` + "\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseImportRows(tt.format, strings.NewReader(tt.input))
+			if err != nil || len(rows) != 1 || rows[0].err != nil {
+				t.Fatalf("parseImportRows() = %+v, %v", rows, err)
+			}
+			if got := importTagsReplacer(existing, rows[0].row) != nil; got != tt.wantReplace {
+				t.Errorf("replaces tags = %v, want %v", got, tt.wantReplace)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"net"
+	"shortlink-go/internal/model"
+	"shortlink-go/pkg/clientip"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// internalNetworks 启动时由 InitInternalNetworks 解析的 stats.internal_cidrs
+var internalNetworks []*net.IPNet
+
+// InitInternalNetworks 解析 stats.internal_cidrs，不带掩码的地址视为单个 IP，任一条目无法解析时返回错误
+func InitInternalNetworks() error {
+	networks, err := clientip.ParseCIDRs(viper.GetStringSlice("stats.internal_cidrs"))
+	if err != nil {
+		return err
+	}
+	internalNetworks = networks
+	return nil
+}
+
+// IsInternalIP 判断访客 IP 是否属于内部网络（办公室、测试环境等）：全局配置的网段，或短链自身配置的网段
+func IsInternalIP(ip string, shortLink *model.ShortLink) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	if clientip.ContainsIP(internalNetworks, parsed) {
+		return true
+	}
+
+	// 短链的网段在写入时已校验，这里无法解析的条目直接忽略
+	for _, entry := range shortLink.InternalCIDRs {
+		if network, err := clientip.ParseCIDR(entry); err == nil && network != nil && network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCIDRs 去掉条目两端的空白，空列表存为空值
+func normalizeCIDRs(entries []string) model.CIDRList {
+	if len(entries) == 0 {
+		return nil
+	}
+	cidrs := make(model.CIDRList, 0, len(entries))
+	for _, entry := range entries {
+		cidrs = append(cidrs, strings.TrimSpace(entry))
+	}
+	return cidrs
+}
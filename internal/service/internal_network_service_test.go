@@ -0,0 +1,44 @@
+package service
+
+import (
+	"shortlink-go/internal/model"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestIsInternalIP(t *testing.T) {
+	viper.Set("stats.internal_cidrs", []string{"10.0.0.0/8", "192.168.1.5"})
+	defer viper.Set("stats.internal_cidrs", nil)
+	if err := InitInternalNetworks(); err != nil {
+		t.Fatalf("InitInternalNetworks() error = %v", err)
+	}
+	defer func() { internalNetworks = nil }()
+
+	link := &model.ShortLink{InternalCIDRs: model.CIDRList{"203.0.113.0/24", "2001:db8::1"}}
+	tests := []struct {
+		ip   string
+		link *model.ShortLink
+		want bool
+	}{
+		{"10.1.2.3", &model.ShortLink{}, true},
+		{"192.168.1.5", &model.ShortLink{}, true},
+		{"192.168.1.6", &model.ShortLink{}, false},
+		{"203.0.113.7", &model.ShortLink{}, false},
+		{"203.0.113.7", link, true},
+		{"2001:db8::1", link, true},
+		{"2001:db8::2", link, false},
+		{"10.1.2.3", link, true},
+		{"not-an-ip", link, false},
+	}
+	for _, tt := range tests {
+		if got := IsInternalIP(tt.ip, tt.link); got != tt.want {
+			t.Errorf("IsInternalIP(%s, %v) = %v, want %v", tt.ip, tt.link.InternalCIDRs, got, tt.want)
+		}
+	}
+
+	viper.Set("stats.internal_cidrs", []string{"10.0.0.0/8", "office"})
+	if err := InitInternalNetworks(); err == nil {
+		t.Error("InitInternalNetworks() with invalid entry should fail")
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/response"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// shortLinkSortColumns 排序字段与数据库列的对应关系
+var shortLinkSortColumns = map[string]string{
+	"id":        "short_links.id",
+	"createdAt": "short_links.created_at",
+	"updatedAt": "short_links.updated_at",
+	"totalPv":   "short_links.total_pv",
+	"totalUv":   "short_links.total_uv",
+}
+
+// listCursor 游标内容，编码后对调用方不透明；排序条件必须与生成游标时一致
+type listCursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Value     string `json:"v"` // 上一页最后一条记录的排序字段值
+	ID        uint   `json:"i"` // 上一页最后一条记录的 ID（排序字段相同时用于定位）
+}
+
+// normalizeShortLinkSort 返回排序字段与方向，默认 id DESC
+func normalizeShortLinkSort(query dto.ShortLinkQuery) (string, string) {
+	sortBy := query.SortBy
+	if _, ok := shortLinkSortColumns[sortBy]; !ok {
+		sortBy = "id"
+	}
+	sortOrder := query.SortOrder
+	if sortOrder != dto.SortOrderAsc {
+		sortOrder = dto.SortOrderDesc
+	}
+	return sortBy, sortOrder
+}
+
+// shortLinkOrderClause 生成 ORDER BY 子句，以 id 作为第二排序键保证顺序稳定
+func shortLinkOrderClause(query dto.ShortLinkQuery) string {
+	sortBy, sortOrder := normalizeShortLinkSort(query)
+	column := shortLinkSortColumns[sortBy]
+	if sortBy == "id" {
+		return fmt.Sprintf("%s %s", column, sortOrder)
+	}
+	return fmt.Sprintf("%s %s, short_links.id %s", column, sortOrder, sortOrder)
+}
+
+// listShortLinksByCursor 基于排序键的游标分页（keyset），不统计总数，翻页开销与页码无关
+func listShortLinksByCursor(ctx context.Context, size int, query dto.ShortLinkQuery, cursor string) (*response.PageResponse[model.ShortLink], error) {
+	sortBy, sortOrder := normalizeShortLinkSort(query)
+	column := shortLinkSortColumns[sortBy]
+
+	db := applyShortLinkFilters(repository.DB.Model(&model.ShortLink{}), query)
+
+	if cursor != "" {
+		decoded, err := decodeListCursor(cursor)
+		if err != nil || decoded.SortBy != sortBy || decoded.SortOrder != sortOrder {
+			message := i18n.T(ctx, "error.cursor_invalid", nil)
+			return nil, apperrors.InvalidRequestError(message)
+		}
+		value, err := parseCursorValue(sortBy, decoded.Value)
+		if err != nil {
+			message := i18n.T(ctx, "error.cursor_invalid", nil)
+			return nil, apperrors.InvalidRequestError(message)
+		}
+
+		op := "<"
+		if sortOrder == dto.SortOrderAsc {
+			op = ">"
+		}
+		db = db.Where(fmt.Sprintf("(%s %s ?) OR (%s = ? AND short_links.id %s ?)", column, op, column, op),
+			value, value, decoded.ID)
+	}
+
+	// 多查一条用于判断是否还有下一页
+	var links []model.ShortLink
+	if err := db.
+		Preload("Tags").
+		Limit(size + 1).
+		Order(shortLinkOrderClause(query)).
+		Find(&links).Error; err != nil {
+		logging.Logger.Error("游标分页查询短链失败", zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	nextCursor := ""
+	if len(links) > size {
+		links = links[:size]
+		last := links[size-1]
+		nextCursor = encodeListCursor(listCursor{
+			SortBy:    sortBy,
+			SortOrder: sortOrder,
+			Value:     cursorValueOf(sortBy, &last),
+			ID:        last.ID,
+		})
+	}
+
+	return &response.PageResponse[model.ShortLink]{
+		Size:       size,
+		List:       links,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func encodeListCursor(cursor listCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(cursor string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	var decoded listCursor
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// cursorValueOf 取出记录的排序字段值
+func cursorValueOf(sortBy string, link *model.ShortLink) string {
+	switch sortBy {
+	case "createdAt":
+		return link.CreatedAt.Format(time.RFC3339Nano)
+	case "updatedAt":
+		return link.UpdatedAt.Format(time.RFC3339Nano)
+	case "totalPv":
+		return strconv.FormatUint(link.TotalPV, 10)
+	case "totalUv":
+		return strconv.FormatUint(link.TotalUV, 10)
+	default:
+		return strconv.FormatUint(uint64(link.ID), 10)
+	}
+}
+
+// parseCursorValue 将游标中的排序字段值还原为查询参数
+func parseCursorValue(sortBy, value string) (interface{}, error) {
+	switch sortBy {
+	case "createdAt", "updatedAt":
+		return time.Parse(time.RFC3339Nano, value)
+	default:
+		return strconv.ParseUint(value, 10, 64)
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/internal/view"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/utils"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// errUnsafeTargetURL 目标地址不是 http/https 时拒绝渲染 HTML 页面
+var errUnsafeTargetURL = errors.New("target url scheme is not http or https")
+
+// IsHTMLRedirectMode 判断跳转方式是否通过 HTML 页面完成跳转
+func IsHTMLRedirectMode(mode string) bool {
+	switch mode {
+	case model.RedirectModeMeta, model.RedirectModeJS, model.RedirectModeFrame:
+		return true
+	default:
+		return false
+	}
+}
+
+// RedirectPageHeaders 返回 HTML 页面的附加响应头：先取 redirect.html.headers.default，再由对应页面（跳转方式或 preview）的配置覆盖
+func RedirectPageHeaders(page string) map[string]string {
+	headers := viper.GetStringMapString("redirect.html.headers.default")
+	for name, value := range viper.GetStringMapString("redirect.html.headers." + page) {
+		headers[name] = value
+	}
+	return headers
+}
+
+// RenderRedirectPage 渲染短链的 HTML 跳转页面，targetURL 为已生成的最终跳转地址
+func RenderRedirectPage(shortLink *model.ShortLink, targetURL string) ([]byte, error) {
+	// 跳转页面会把地址写入 meta refresh 与脚本，渲染前再次确认协议
+	if !utils.IsHTTPURL(targetURL) {
+		return nil, errUnsafeTargetURL
+	}
+	title := shortLink.Description
+	if title == "" {
+		title = shortLink.ShortCode
+	}
+
+	var buf bytes.Buffer
+	err := view.RenderRedirect(&buf, shortLink.RedirectMode, view.RedirectPage{
+		Title:     title,
+		TargetURL: targetURL,
+		Delay:     viper.GetInt("redirect.html.meta_delay"),
+		Head:      template.HTML(viper.GetString("redirect.html.head")), // 仅来自服务端配置，不含用户输入
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderPreviewPage 渲染短链预览页面（目标地址、创建日期与访问量），文案按请求语言本地化
+func RenderPreviewPage(ctx context.Context, shortLink *model.ShortLink, shortCode, targetURL string) ([]byte, error) {
+	if !utils.IsHTTPURL(targetURL) {
+		return nil, errUnsafeTargetURL
+	}
+	var buf bytes.Buffer
+	err := view.RenderPreview(&buf, view.PreviewPage{
+		Lang:      i18n.T(ctx, "preview.lang", nil),
+		ShortCode: shortCode,
+		TargetURL: targetURL,
+		CreatedAt: shortLink.CreatedAt.Format(i18n.T(ctx, "preview.date_layout", nil)),
+		Clicks:    previewClicks(shortLink),
+		Text: view.PreviewText{
+			Title:       i18n.T(ctx, "preview.title", nil),
+			Destination: i18n.T(ctx, "preview.destination", nil),
+			CreatedAt:   i18n.T(ctx, "preview.created_at", nil),
+			Clicks:      i18n.T(ctx, "preview.clicks", nil),
+			Notice:      i18n.T(ctx, "preview.notice", nil),
+			Continue:    i18n.T(ctx, "preview.continue", nil),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// previewClicks 预览页面展示的访问次数：优先取 Redis 中的实时计数，失败时使用数据库中的汇总值
+func previewClicks(shortLink *model.ShortLink) uint64 {
+	conn := repository.RedisPool.Get()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.Logger.Warn("Redis connection close failed", zap.Error(err))
+		}
+	}()
+
+	if pv, err := GetTotalPv(conn, shortLink.ShortCode); err == nil && pv > shortLink.TotalPV {
+		return pv
+	}
+	return shortLink.TotalPV
+}
+
+// defaultSocialCrawlers 未配置 redirect.social_crawlers 时识别为社交平台抓取程序的 User-Agent 片段
+var defaultSocialCrawlers = []string{
+	"facebookexternalhit", "Facebot", "Twitterbot", "Slackbot", "LinkedInBot", "Discordbot",
+	"TelegramBot", "WhatsApp", "SkypeUriPreview", "Pinterestbot", "redditbot", "Embedly", "vkShare",
+}
+
+// IsSocialCrawler 判断 User-Agent 是否来自社交平台的链接预览抓取程序（不区分大小写）
+func IsSocialCrawler(userAgent string) bool {
+	if userAgent == "" {
+		return false
+	}
+	crawlers := defaultSocialCrawlers
+	if viper.IsSet("redirect.social_crawlers") {
+		crawlers = viper.GetStringSlice("redirect.social_crawlers")
+	}
+
+	userAgent = strings.ToLower(userAgent)
+	for _, crawler := range crawlers {
+		if crawler != "" && strings.Contains(userAgent, strings.ToLower(crawler)) {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicShortLinkURL 用 shortlink.base_url 拼出短链的公开地址，path 为已转义的请求路径；未配置时返回空字符串
+// 不使用请求中的 Host，避免客户端伪造的主机名出现在页面中
+func PublicShortLinkURL(path string) string {
+	base := strings.TrimRight(viper.GetString("shortlink.base_url"), "/")
+	if base == "" {
+		return ""
+	}
+	return base + "/" + strings.TrimPrefix(path, "/")
+}
+
+// RenderOpenGraphPage 渲染短链的 Open Graph 预览页面，未配置的标题使用短链说明或短码
+func RenderOpenGraphPage(shortLink *model.ShortLink, shortURL, targetURL string) ([]byte, error) {
+	if !utils.IsHTTPURL(targetURL) {
+		return nil, errUnsafeTargetURL
+	}
+	title := shortLink.OpenGraph.Title
+	if title == "" {
+		title = shortLink.Description
+	}
+	if title == "" {
+		title = shortLink.ShortCode
+	}
+
+	var buf bytes.Buffer
+	err := view.RenderOpenGraph(&buf, view.OpenGraphPage{
+		URL:         shortURL,
+		TargetURL:   targetURL,
+		Title:       title,
+		Description: shortLink.OpenGraph.Description,
+		Image:       shortLink.OpenGraph.Image,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"net/url"
+	"path"
+	"shortlink-go/internal/model"
+	"shortlink-go/pkg/utils"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// maxPrefixMatchDepth 前缀匹配时最多尝试的上级路径数量
+const maxPrefixMatchDepth = 8
+
+// defaultCountryHeaders 未配置 redirect.country_headers 时读取访客国家代码的请求头（由 CDN 或网关写入）
+var defaultCountryHeaders = []string{"CF-IPCountry", "CloudFront-Viewer-Country", "X-Country-Code"}
+
+// RedirectContext 跳转请求中可用于生成最终地址的信息
+type RedirectContext struct {
+	Suffix   string     // 前缀匹配时短码之后剩余的路径
+	RawQuery string     // 访问时的查询串
+	Query    url.Values // 解析后的查询参数，供模板占位符使用
+	Language string     // Accept-Language 中优先级最高的语言
+	Country  string     // 访客国家代码
+}
+
+// CountryHeaders 返回读取国家代码的请求头列表
+func CountryHeaders() []string {
+	if viper.IsSet("redirect.country_headers") {
+		return viper.GetStringSlice("redirect.country_headers")
+	}
+	return defaultCountryHeaders
+}
+
+// ResolveShortLink 根据请求路径查找短链：先精确匹配（含别名），再按路径逐级向上查找开启了路径透传的短链
+// 返回匹配到的短码（可能是别名）以及短码之后剩余的路径（以 / 开头，精确匹配时为空）
+// 透传的路径可能包含短码不允许的字符（如 file.pdf），不合法的层级直接跳过；
+// 上级路径未命中时不写入空值缓存，避免一次访问产生多个空值 key
+func ResolveShortLink(requestPath string) (*model.ShortLink, string, string, bool) {
+	if utils.ValidateShortCode(requestPath) == nil {
+		if shortLink, ok := lookupShortLink(requestPath, true); ok {
+			return shortLink, requestPath, "", true
+		}
+	}
+
+	end := len(requestPath)
+	for depth := 0; depth < maxPrefixMatchDepth; depth++ {
+		i := strings.LastIndex(requestPath[:end], "/")
+		if i <= 0 {
+			break
+		}
+		end = i
+		code := requestPath[:i]
+		if utils.ValidateShortCode(code) != nil {
+			continue
+		}
+		if shortLink, ok := lookupShortLink(code, false); ok {
+			if shortLink.PathPassthrough {
+				return shortLink, code, requestPath[i:], true
+			}
+			// 找到了短链但未开启路径透传，不再继续向上查找
+			break
+		}
+	}
+	return nil, "", "", false
+}
+
+// BuildRedirectURL 生成最终跳转地址：先填充模板占位符，再合并 UTM 参数，最后按短链配置追加剩余路径与访问时的查询参数
+// 目标地址中已有的同名参数优先，不会被访问参数覆盖
+func BuildRedirectURL(shortLink *model.ShortLink, rc RedirectContext) string {
+	target := utils.RenderURLTemplate(shortLink.TargetURL, func(name string) string {
+		switch name {
+		case "code":
+			return shortLink.ShortCode
+		case "lang":
+			return rc.Language
+		case "country":
+			return rc.Country
+		default:
+			return rc.Query.Get(name)
+		}
+	})
+	target = shortLink.WithUTM(target)
+
+	if rc.Suffix != "" && shortLink.PathPassthrough {
+		if u, err := url.Parse(target); err == nil {
+			// 以根路径清理，防止通过 .. 跳出目标地址的路径
+			cleaned := path.Clean("/" + rc.Suffix)
+			if strings.HasSuffix(rc.Suffix, "/") && cleaned != "/" {
+				cleaned += "/"
+			}
+			u.Path = strings.TrimSuffix(u.Path, "/") + cleaned
+			u.RawPath = ""
+			target = u.String()
+		}
+	}
+
+	if rc.RawQuery != "" && shortLink.QueryPassthrough {
+		target = utils.MergeQueryParams(target, utils.ParseQueryPairs(rc.RawQuery))
+	}
+	return target
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"net/http"
+	"shortlink-go/constant"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/utils"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+)
+
+// migrateShortCodeScript 原子地将统计 key 从旧短码迁移到新短码，并删除相关跳转缓存
+// KEYS：前 2*n 个为成对的 (源 key, 目标 key)，随后 m 个为每日 PV 哈希 key，其余为需要删除的缓存 key
+// ARGV：n、m、旧短码、新短码
+var migrateShortCodeScript = redis.NewScript(-1, `
+local n = tonumber(ARGV[1])
+local m = tonumber(ARGV[2])
+for i = 1, n do
+  local src, dst = KEYS[2 * i - 1], KEYS[2 * i]
+  if redis.call('EXISTS', src) == 1 then
+    redis.call('RENAME', src, dst)
+  end
+end
+for i = 2 * n + 1, 2 * n + m do
+  local pv = redis.call('HGET', KEYS[i], ARGV[3])
+  if pv then
+    redis.call('HINCRBY', KEYS[i], ARGV[4], pv)
+    redis.call('HDEL', KEYS[i], ARGV[3])
+  end
+end
+for i = 2 * n + m + 1, #KEYS do
+  redis.call('DEL', KEYS[i])
+end
+return 1
+`)
+
+// RenameShortCode 修改短链的短码，迁移 Redis 中的统计数据，可选保留旧短码作为别名
+func RenameShortCode(ctx context.Context, id uint, newShortCode string, keepAlias bool, expectedVersion uint) (*model.ShortLink, error) {
+	if err := utils.ValidateShortCode(newShortCode); err != nil {
+		message := i18n.T(ctx, err.Error(), nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	existing, err := GetShortLink(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedVersion == 0 {
+		expectedVersion = existing.Version
+	}
+	if existing.Version != expectedVersion {
+		return nil, versionConflictError(ctx)
+	}
+
+	oldShortCode := existing.ShortCode
+	if newShortCode == oldShortCode {
+		message := i18n.T(ctx, "error.shortcode_unchanged", nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	// 重命名前的缓存 key（含旧短码与现有别名），迁移完成后统一删除
+	staleCacheKeys := shortLinkCacheKeys(existing)
+
+	migrated := false
+	if err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		// 新短码不能被其他短链或别名占用（本短链自己的别名除外，会被转为主短码）
+		taken, err := isShortCodeTaken(tx, newShortCode, existing.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			message := i18n.T(ctx, "error.shortcode_exists", nil)
+			return apperrors.BusinessError(http.StatusConflict, message)
+		}
+
+		result := tx.Model(&model.ShortLink{}).
+			Where("id = ? AND version = ?", existing.ID, expectedVersion).
+			Updates(map[string]interface{}{
+				"short_code": newShortCode,
+				"updated_at": time.Now(),
+				"version":    gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return versionConflictError(ctx)
+		}
+
+		// 新短码原本是本短链的别名时，移除该别名
+		if err := tx.Where("short_link_id = ? AND code = ?", existing.ID, newShortCode).
+			Delete(&model.ShortLinkAlias{}).Error; err != nil {
+			return err
+		}
+
+		if keepAlias {
+			if err := tx.Create(&model.ShortLinkAlias{
+				ShortLinkID: existing.ID,
+				Code:        oldShortCode,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Redis 迁移放在事务提交前，失败时回滚数据库修改
+		if err := migrateShortCodeRedisKeys(oldShortCode, newShortCode, staleCacheKeys); err != nil {
+			return err
+		}
+		migrated = true
+		return nil
+	}); err != nil {
+		// Redis 已迁移但事务提交失败时，把统计数据迁回旧短码
+		if migrated {
+			if undoErr := migrateShortCodeRedisKeys(newShortCode, oldShortCode, []string{constant.GetShortCodeKey(newShortCode)}); undoErr != nil {
+				logging.Logger.Error("回迁短码 Redis 数据失败",
+					zap.Uint("id", existing.ID),
+					zap.String("old_short_code", oldShortCode),
+					zap.String("new_short_code", newShortCode),
+					zap.Error(undoErr))
+			}
+		}
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		logging.Logger.Error("修改短码失败",
+			zap.Uint("id", existing.ID),
+			zap.String("old_short_code", oldShortCode),
+			zap.String("new_short_code", newShortCode),
+			zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	existing.ShortCode = newShortCode
+	existing.Version = expectedVersion + 1
+	return existing, nil
+}
+
+// migrateShortCodeRedisKeys 迁移 total_pv、total_uv、bot_pv、internal_pv、opt_out_pv 以及最近几天的每日 PV/UV 到新短码
+func migrateShortCodeRedisKeys(oldShortCode, newShortCode string, staleCacheKeys []string) error {
+	conn := repository.RedisPool.Get()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.Logger.Error("关闭 Redis 连接失败",
+				zap.Error(err),
+				zap.String("operation", "close"),
+				zap.String("connection_type", "redis"),
+			)
+		}
+	}()
+
+	dates := constant.GetRecentDateKeys(constant.DailyKeyTTLDays)
+
+	renamePairs := []string{
+		constant.GetTotalPVKey(oldShortCode), constant.GetTotalPVKey(newShortCode),
+		constant.GetTotalUVKey(oldShortCode), constant.GetTotalUVKey(newShortCode),
+		constant.GetBotPVKey(oldShortCode), constant.GetBotPVKey(newShortCode),
+		constant.GetInternalPVKey(oldShortCode), constant.GetInternalPVKey(newShortCode),
+		constant.GetOptOutPVKey(oldShortCode), constant.GetOptOutPVKey(newShortCode),
+	}
+	for _, date := range dates {
+		renamePairs = append(renamePairs,
+			constant.GetDailyUVKey(oldShortCode, date), constant.GetDailyUVKey(newShortCode, date))
+	}
+
+	hashKeys := make([]string, 0, len(dates))
+	for _, date := range dates {
+		hashKeys = append(hashKeys, constant.GetDailyPVKey(date))
+	}
+
+	// 新短码之前可能缓存过空值（防穿透），一并删除
+	cacheKeys := append(staleCacheKeys, constant.GetShortCodeKey(newShortCode))
+
+	keys := make([]string, 0, len(renamePairs)+len(hashKeys)+len(cacheKeys))
+	keys = append(keys, renamePairs...)
+	keys = append(keys, hashKeys...)
+	keys = append(keys, cacheKeys...)
+
+	args := make([]interface{}, 0, len(keys)+5)
+	args = append(args, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	args = append(args, len(renamePairs)/2, len(hashKeys), oldShortCode, newShortCode)
+
+	if _, err := migrateShortCodeScript.Do(conn, args...); err != nil {
+		logging.Logger.Error("迁移短码 Redis 数据失败",
+			zap.String("old_short_code", oldShortCode),
+			zap.String("new_short_code", newShortCode),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
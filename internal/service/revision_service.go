@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+
+	"go.uber.org/zap"
+)
+
+// recordShortLinkRevision 为短链当前的跳转规则追加一个新版本（需在事务中调用）
+func recordShortLinkRevision(tx *gorm.DB, shortLink *model.ShortLink) error {
+	latest, err := latestShortLinkRevision(tx, shortLink.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Create(newShortLinkRevision(shortLink, latest+1)).Error
+}
+
+// seedShortLinkRevision 短链还没有任何历史版本时（早于版本功能创建），以数据库中修改前的规则补记第一个版本，
+// 保证修改后仍可回滚到原配置；需在同一事务中、写入修改之前调用
+func seedShortLinkRevision(tx *gorm.DB, id uint) error {
+	latest, err := latestShortLinkRevision(tx, id)
+	if err != nil || latest > 0 {
+		return err
+	}
+
+	var previous model.ShortLink
+	if err := tx.First(&previous, id).Error; err != nil {
+		return err
+	}
+	return tx.Create(newShortLinkRevision(&previous, 1)).Error
+}
+
+// latestShortLinkRevision 查询短链当前最大的版本号，没有历史版本时返回 0
+func latestShortLinkRevision(tx *gorm.DB, id uint) (uint, error) {
+	var latest uint
+	err := tx.Model(&model.ShortLinkRevision{}).
+		Where("short_link_id = ?", id).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&latest).Error
+	return latest, err
+}
+
+// newShortLinkRevision 以短链的跳转规则生成指定版本号的历史记录
+func newShortLinkRevision(shortLink *model.ShortLink, revision uint) *model.ShortLinkRevision {
+	return &model.ShortLinkRevision{
+		ShortLinkID:      shortLink.ID,
+		Revision:         revision,
+		TargetURL:        shortLink.TargetURL,
+		RedirectCode:     shortLink.RedirectCode,
+		RedirectMode:     shortLink.RedirectMode,
+		UTM:              shortLink.UTM,
+		OpenGraph:        shortLink.OpenGraph,
+		QueryPassthrough: shortLink.QueryPassthrough,
+		PathPassthrough:  shortLink.PathPassthrough,
+	}
+}
+
+// ListShortLinkRevisions 查询短链的历史版本（按版本号倒序）
+func ListShortLinkRevisions(ctx context.Context, id uint) ([]model.ShortLinkRevision, error) {
+	var existing model.ShortLink
+	if err := repository.DB.Select("id").First(&existing, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			message := i18n.T(ctx, "error.shortcode_not_found", nil)
+			return nil, apperrors.BusinessError(http.StatusNotFound, message)
+		}
+		logging.Logger.Error("查询短链失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	revisions := make([]model.ShortLinkRevision, 0)
+	if err := repository.DB.
+		Where("short_link_id = ?", id).
+		Order("revision DESC").
+		Find(&revisions).Error; err != nil {
+		logging.Logger.Error("查询短链历史版本失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	return revisions, nil
+}
+
+// RollbackShortLink 将短链回滚到指定历史版本（复用 PatchShortLink 的更新流程，回滚本身也会生成新版本）
+func RollbackShortLink(ctx context.Context, id uint, revision uint, expectedVersion uint) (*model.ShortLink, error) {
+	var target model.ShortLinkRevision
+	if err := repository.DB.
+		Where("short_link_id = ? AND revision = ?", id, revision).
+		First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			message := i18n.T(ctx, "error.revision_not_found", nil)
+			return nil, apperrors.BusinessError(http.StatusNotFound, message)
+		}
+		logging.Logger.Error("查询短链历史版本失败",
+			zap.Uint("id", id),
+			zap.Uint("revision", revision),
+			zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	// 回滚全部跳转规则，而不仅是目标地址和状态码
+	redirectMode := target.RedirectMode
+	if redirectMode == "" {
+		redirectMode = model.RedirectModeHTTP
+	}
+	patch := dto.PatchShortLinkRequest{}
+	patch.TargetURL = dto.PatchField[string]{Set: true, Value: target.TargetURL}
+	patch.RedirectCode = dto.PatchField[int]{Set: true, Value: target.RedirectCode}
+	patch.RedirectMode = dto.PatchField[string]{Set: true, Value: redirectMode}
+	patch.UTM = dto.PatchField[model.UTMParams]{Set: true, Value: target.UTM}
+	patch.OpenGraph = dto.PatchField[model.OpenGraph]{Set: true, Value: target.OpenGraph}
+	patch.QueryPassthrough = dto.PatchField[bool]{Set: true, Value: target.QueryPassthrough}
+	patch.PathPassthrough = dto.PatchField[bool]{Set: true, Value: target.PathPassthrough}
+	return PatchShortLink(ctx, id, patch, expectedVersion)
+}
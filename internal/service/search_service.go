@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/internal/search"
+	"shortlink-go/pkg/logging"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// 检索后端（search.backend）
+const (
+	SearchBackendMySQL  = "mysql"  // MySQL FULLTEXT 索引（默认）
+	SearchBackendMemory = "memory" // 每次从数据库加载全部短链在内存中匹配，仅适用于测试或数据量很小的环境
+)
+
+// maxSearchLimit 单次检索最多返回的条数
+const maxSearchLimit = 100
+
+// mysqlShortLinkSearcher 基于 FULLTEXT 索引（ngram 分词）检索短码、目标地址、说明、备注和标签
+type mysqlShortLinkSearcher struct{}
+
+// Search 布尔模式下任一关键词命中即返回，得分为短链字段与标签的相关度之和
+func (mysqlShortLinkSearcher) Search(_ context.Context, query string, limit int) ([]search.Hit, error) {
+	terms := search.Tokenize(query)
+	if len(terms) == 0 {
+		return []search.Hit{}, nil
+	}
+	against := search.BooleanQuery(terms)
+
+	const linkMatch = "MATCH(short_links.short_code, short_links.target_url, short_links.description, short_links.notes) " +
+		"AGAINST (? IN BOOLEAN MODE)"
+
+	hits := make([]search.Hit, 0)
+	err := repository.DB.Model(&model.ShortLink{}).
+		Select("short_links.id, "+linkMatch+" + COALESCE(tm.score, 0) AS score", against).
+		Joins("LEFT JOIN (SELECT short_link_tags.short_link_id, SUM(MATCH(tags.name) AGAINST (? IN BOOLEAN MODE)) AS score "+
+			"FROM short_link_tags JOIN tags ON tags.id = short_link_tags.tag_id "+
+			"WHERE MATCH(tags.name) AGAINST (? IN BOOLEAN MODE) "+
+			"GROUP BY short_link_tags.short_link_id) tm ON tm.short_link_id = short_links.id", against, against).
+		Where(linkMatch+" OR tm.short_link_id IS NOT NULL", against).
+		Order("score DESC, short_links.id DESC").
+		Limit(limit).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// memoryShortLinkSearcher 加载全部短链后使用 search.MemorySearcher 匹配
+type memoryShortLinkSearcher struct{}
+
+func (memoryShortLinkSearcher) Search(ctx context.Context, query string, limit int) ([]search.Hit, error) {
+	var links []model.ShortLink
+	if err := repository.DB.Preload("Tags").Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	docs := make([]search.Document, 0, len(links))
+	for i := range links {
+		docs = append(docs, searchDocumentOf(&links[i]))
+	}
+	return search.NewMemorySearcher(docs...).Search(ctx, query, limit)
+}
+
+// shortLinkSearcher 根据配置选择检索后端
+func shortLinkSearcher() search.Searcher {
+	if viper.GetString("search.backend") == SearchBackendMemory {
+		return memoryShortLinkSearcher{}
+	}
+	return mysqlShortLinkSearcher{}
+}
+
+// SearchShortLinks 全文检索短链（不含回收站），返回结果按相关度排序并附带命中片段
+func SearchShortLinks(ctx context.Context, query string, limit int) ([]dto.ShortLinkSearchResult, error) {
+	terms := search.Tokenize(query)
+	if len(terms) == 0 {
+		message := i18n.T(ctx, "error.search_query_required", nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+	if limit < 1 || limit > maxSearchLimit {
+		limit = 20
+	}
+
+	hits, err := shortLinkSearcher().Search(ctx, query, limit)
+	if err != nil {
+		logging.Logger.Error("全文检索短链失败", zap.String("query", query), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	results := make([]dto.ShortLinkSearchResult, 0, len(hits))
+	if len(hits) == 0 {
+		return results, nil
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	var links []model.ShortLink
+	if err := repository.DB.Preload("Tags").Where("id IN ?", ids).Find(&links).Error; err != nil {
+		logging.Logger.Error("查询检索结果失败", zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	byID := make(map[uint]*model.ShortLink, len(links))
+	for i := range links {
+		byID[links[i].ID] = &links[i]
+	}
+
+	// 保持检索后端给出的相关度顺序
+	for _, hit := range hits {
+		link, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, dto.ShortLinkSearchResult{
+			ShortLink:  *link,
+			Score:      hit.Score,
+			Highlights: highlightShortLink(link, terms),
+		})
+	}
+	return results, nil
+}
+
+// highlightShortLink 生成各字段的命中片段，未命中的字段不出现在结果中
+func highlightShortLink(link *model.ShortLink, terms []string) map[string][]string {
+	highlights := make(map[string][]string)
+	fields := map[string]string{
+		"shortCode":   link.ShortCode,
+		"targetUrl":   link.TargetURL,
+		"description": link.Description,
+		"notes":       link.Notes,
+	}
+	for name, text := range fields {
+		if fragments := search.Highlight(text, terms); fragments != nil {
+			highlights[name] = fragments
+		}
+	}
+	for _, tag := range link.Tags {
+		highlights["tags"] = append(highlights["tags"], search.Highlight(tag.Name, terms)...)
+	}
+	if len(highlights["tags"]) == 0 {
+		delete(highlights, "tags")
+	}
+	return highlights
+}
+
+func searchDocumentOf(link *model.ShortLink) search.Document {
+	tags := make([]string, len(link.Tags))
+	for i, tag := range link.Tags {
+		tags[i] = tag.Name
+	}
+	return search.Document{
+		ID:          link.ID,
+		ShortCode:   link.ShortCode,
+		TargetURL:   link.TargetURL,
+		Description: link.Description,
+		Notes:       link.Notes,
+		Tags:        tags,
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"shortlink-go/internal/repository"
 	"shortlink-go/pkg/logging"
 	"shortlink-go/pkg/utils"
+	"strings"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
@@ -22,49 +23,114 @@ import (
 	"shortlink-go/response"
 )
 
-// CreateShortLink 创建短链
-func CreateShortLink(ctx context.Context, req dto.CreateShortLinkRequest) error {
+// CreateShortLink 创建短链，并提示规范化目标地址相同的已有短链
+// req.ReuseExisting 为 true 且存在跳转设置一致的可用相同目标短链时，直接返回该短链
+func CreateShortLink(ctx context.Context, req dto.CreateShortLinkRequest) (*dto.CreateShortLinkResponse, error) {
 	// Gin 标准验证
 	if err := req.Validate(); err != nil {
 		message := i18n.T(ctx, err.Error(), nil)
-		return apperrors.InvalidRequestError(message)
+		return nil, apperrors.InvalidRequestError(message)
 	}
 
-	// 检查短链是否已存在
-	var existing model.ShortLink
-	if err := repository.DB.Where("short_code = ?", req.ShortCode).First(&existing).Error; err == nil {
-		logging.Logger.Info("短链已存在", zap.Error(err))
-		return apperrors.BusinessError(http.StatusConflict, "短链已存在")
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+	canonicalHash := canonicalTargetHash(req.TargetURL)
+	duplicates, err := findDuplicateShortLinks(canonicalHash)
+	if err != nil {
+		logging.Logger.Info("查询重复目标短链失败", zap.Error(err))
+		return nil, apperrors.SystemErrorDefault()
+	}
+
+	if req.RedirectMode == "" {
+		req.RedirectMode = model.RedirectModeHTTP
+	}
+
+	resp := &dto.CreateShortLinkResponse{Duplicates: make([]dto.DuplicateShortLink, 0, len(duplicates))}
+	now := time.Now()
+	for i := range duplicates {
+		// 只复用跳转效果完全一致的短链，否则作为候选返回给调用方
+		if req.ReuseExisting && !duplicates[i].Disabled && !duplicates[i].IsExpired(now) &&
+			sameRedirectSettings(&duplicates[i], req) {
+			logging.Logger.Info("复用目标相同的已有短链",
+				zap.Uint("id", duplicates[i].ID),
+				zap.String("short_code", duplicates[i].ShortCode))
+			return &dto.CreateShortLinkResponse{
+				ShortLink:  &duplicates[i],
+				Reused:     true,
+				Duplicates: []dto.DuplicateShortLink{},
+			}, nil
+		}
+		resp.Duplicates = append(resp.Duplicates, dto.DuplicateShortLink{
+			ID:        duplicates[i].ID,
+			ShortCode: duplicates[i].ShortCode,
+			TargetURL: duplicates[i].TargetURL,
+		})
+	}
+
+	// 检查短链是否已存在（短码与别名共用同一命名空间）
+	if taken, err := isShortCodeTaken(repository.DB, req.ShortCode, 0); err != nil {
 		logging.Logger.Info("查询短链失败", zap.Error(err))
-		return apperrors.SystemErrorDefault()
+		return nil, apperrors.SystemErrorDefault()
+	} else if taken {
+		logging.Logger.Info("短链已存在", zap.String("short_code", req.ShortCode))
+		return nil, apperrors.BusinessError(http.StatusConflict, "短链已存在")
 	}
 
 	// 构建模型
 	shortLink := &model.ShortLink{
-		TargetURL:    req.TargetURL,
-		ShortCode:    req.ShortCode,
-		RedirectCode: req.RedirectCode,
-		Disabled:     req.Disabled, // 默认 false
+		TargetURL:     req.TargetURL,
+		CanonicalHash: canonicalHash,
+		ShortCode:     req.ShortCode,
+		RedirectCode:  req.RedirectCode,
+		RedirectMode:  req.RedirectMode,
+		Disabled:      req.Disabled, // 默认 false
+		ExpiresAt:     req.ExpiresAt,
+		Description:   req.Description,
+		Notes:         req.Notes,
+		UTM:           req.UTM,
+		OpenGraph:     req.OpenGraph,
+
+		QueryPassthrough: req.QueryPassthrough,
+		PathPassthrough:  req.PathPassthrough,
+		InternalCIDRs:    normalizeCIDRs(req.InternalCIDRs),
 	}
 
-	// 数据库持久化
-	if err := repository.DB.Create(shortLink).Error; err != nil {
+	// 数据库持久化（同时记录第一个版本）
+	if err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, req.Tags)
+		if err != nil {
+			return err
+		}
+		shortLink.Tags = tags
+
+		if err := tx.Create(shortLink).Error; err != nil {
+			return err
+		}
+		return recordShortLinkRevision(tx, shortLink)
+	}); err != nil {
 		logging.Logger.Info("数据库操作失败", zap.Error(err))
-		return apperrors.SystemErrorDefault()
+		return nil, apperrors.SystemErrorDefault()
 	}
-	return nil
+
+	shortLink.FinalURL = shortLink.FinalTargetURL()
+	resp.ShortLink = shortLink
+	return resp, nil
+}
+
+// sameRedirectSettings 判断已有短链的跳转设置（状态码、跳转方式、UTM、过期时间、透传开关、预览信息）是否与创建请求一致
+func sameRedirectSettings(link *model.ShortLink, req dto.CreateShortLinkRequest) bool {
+	sameExpiry := (link.ExpiresAt == nil) == (req.ExpiresAt == nil) &&
+		(link.ExpiresAt == nil || link.ExpiresAt.Equal(*req.ExpiresAt))
+	return sameExpiry &&
+		link.RedirectCode == req.RedirectCode &&
+		link.RedirectMode == req.RedirectMode &&
+		link.UTM == req.UTM &&
+		link.OpenGraph == req.OpenGraph &&
+		link.QueryPassthrough == req.QueryPassthrough &&
+		link.PathPassthrough == req.PathPassthrough
 }
 
 // ListShortLinks 支持分页查询短链列表
-func ListShortLinks(
-	ctx context.Context,
-	page, size int,
-	shortCode string,
-	targetUrl string,
-	redirectCode int,
-	disabled *bool,
-) (*response.PageResponse[model.ShortLink], error) {
+// cursor 不为 nil 时使用游标分页（忽略 page，空字符串表示第一页），否则使用 LIMIT/OFFSET 分页
+func ListShortLinks(ctx context.Context, page, size int, query dto.ShortLinkQuery, cursor *string) (*response.PageResponse[model.ShortLink], error) {
 	// 参数校验
 	if page < 1 {
 		page = 1
@@ -73,22 +139,13 @@ func ListShortLinks(
 		size = 10
 	}
 
-	// 构建查询条件
-	db := repository.DB.Model(&model.ShortLink{})
-
-	if shortCode != "" {
-		db = db.Where("short_code LIKE ?", "%"+shortCode+"%")
-	}
-	if targetUrl != "" {
-		db = db.Where("target_url LIKE ?", "%"+targetUrl+"%")
-	}
-	if redirectCode != 0 {
-		db = db.Where("redirect_code = ?", redirectCode)
-	}
-	if disabled != nil {
-		db = db.Where("disabled = ?", *disabled)
+	if cursor != nil {
+		return listShortLinksByCursor(ctx, size, query, *cursor)
 	}
 
+	// 构建查询条件
+	db := applyShortLinkFilters(repository.DB.Model(&model.ShortLink{}), query)
+
 	// 查询总记录数
 	var total int64
 	if err := db.Count(&total).Error; err != nil {
@@ -110,9 +167,10 @@ func ListShortLinks(
 	// 分页查询
 	var links []model.ShortLink
 	if err := db.
+		Preload("Tags").
 		Limit(size).
 		Offset((page - 1) * size).
-		Order("id DESC").
+		Order(shortLinkOrderClause(query)).
 		Find(&links).Error; err != nil {
 		logging.Logger.Error("分页查询短链失败", zap.Error(err))
 		message := i18n.T(ctx, "error.system_error", nil)
@@ -130,13 +188,83 @@ func ListShortLinks(
 	}, nil
 }
 
+// GetShortLink 根据 ID 查询短链详情
+func GetShortLink(ctx context.Context, id uint) (*model.ShortLink, error) {
+	var existing model.ShortLink
+	if err := repository.DB.Preload("Tags").First(&existing, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			message := i18n.T(ctx, "error.shortcode_not_found", nil)
+			return nil, apperrors.BusinessError(http.StatusNotFound, message)
+		}
+		logging.Logger.Error("查询短链失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	return &existing, nil
+}
+
+// applyShortLinkFilters 将列表筛选条件应用到查询上
+func applyShortLinkFilters(db *gorm.DB, query dto.ShortLinkQuery) *gorm.DB {
+	if query.ShortCode != "" {
+		switch query.ShortCodeMatch {
+		case dto.ShortCodeMatchExact:
+			db = db.Where("short_links.short_code = ?", query.ShortCode)
+		case dto.ShortCodeMatchPrefix:
+			// 前缀匹配可以使用 short_code 唯一索引
+			db = db.Where("short_links.short_code LIKE ?", escapeLike(query.ShortCode)+"%")
+		default:
+			db = db.Where("short_links.short_code LIKE ?", "%"+escapeLike(query.ShortCode)+"%")
+		}
+	}
+	if query.TargetURL != "" {
+		db = db.Where("short_links.target_url LIKE ?", "%"+escapeLike(query.TargetURL)+"%")
+	}
+	if query.RedirectCode != 0 {
+		db = db.Where("short_links.redirect_code = ?", query.RedirectCode)
+	}
+	if query.Disabled != nil {
+		db = db.Where("short_links.disabled = ?", *query.Disabled)
+	}
+	if len(query.Tags) > 0 {
+		tagged := repository.DB.Table("short_link_tags").
+			Select("short_link_tags.short_link_id").
+			Joins("JOIN tags ON tags.id = short_link_tags.tag_id").
+			Where("tags.name IN ?", query.Tags)
+		if query.TagMode == dto.TagModeAnd {
+			tagged = tagged.Group("short_link_tags.short_link_id").
+				Having("COUNT(DISTINCT tags.id) = ?", len(query.Tags))
+		}
+		db = db.Where("short_links.id IN (?)", tagged)
+	}
+	if query.CreatedFrom != nil {
+		db = db.Where("short_links.created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		db = db.Where("short_links.created_at <= ?", *query.CreatedTo)
+	}
+	if query.UpdatedFrom != nil {
+		db = db.Where("short_links.updated_at >= ?", *query.UpdatedFrom)
+	}
+	if query.UpdatedTo != nil {
+		db = db.Where("short_links.updated_at <= ?", *query.UpdatedTo)
+	}
+	return db
+}
+
+// escapeLike 转义 LIKE 通配符，用户输入按字面匹配
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // UpdateShortLink 更新短链配置（包含状态可选修改）
-func UpdateShortLink(ctx context.Context, id uint, targetUrl string, redirectCode int, newDisabled *bool) error {
+// expectedVersion 为调用方读取到的版本号，与数据库不一致时返回 409；传 0 表示以本次读取的版本为准
+func UpdateShortLink(ctx context.Context, id uint, targetUrl string, redirectCode int, newDisabled *bool, expectedVersion uint) (*model.ShortLink, error) {
 
 	// 校验目标 URL
 	if err := utils.ValidateTargetURL(targetUrl); err != nil {
 		message := i18n.T(ctx, err.Error(), nil)
-		return apperrors.InvalidRequestError(message)
+		return nil, apperrors.InvalidRequestError(message)
 	}
 
 	// 查询现有短链记录
@@ -147,79 +275,348 @@ func UpdateShortLink(ctx context.Context, id uint, targetUrl string, redirectCod
 				zap.Uint("id", id),
 				zap.String("target_url", targetUrl))
 			message := i18n.T(ctx, "error.shortcode_not_found", nil)
-			return apperrors.BusinessError(http.StatusNotFound, message)
+			return nil, apperrors.BusinessError(http.StatusNotFound, message)
 		}
 		logging.Logger.Error("查询短链失败",
 			zap.Uint("id", id),
 			zap.String("target_url", targetUrl),
 			zap.Error(err))
 		message := i18n.T(ctx, "error.system_error", nil)
-		return apperrors.SystemError(message)
+		return nil, apperrors.SystemError(message)
 	}
 
-	// 判断状态是否需要变更
-	if newDisabled != nil && *newDisabled != existing.Disabled {
-		if *newDisabled {
-			// 禁用：同步统计、备份、清理 Redis
-			if err := DoStatisticalData(&existing, constant.GetDateKey()); err != nil {
-				logging.Logger.Error("禁用时同步统计数据失败",
-					zap.Uint("id", existing.ID),
-					zap.Error(err))
-				return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
-			}
+	// 在产生任何副作用之前先校验版本号
+	if expectedVersion == 0 {
+		expectedVersion = existing.Version
+	}
+	if existing.Version != expectedVersion {
+		logging.Logger.Info("短链版本冲突",
+			zap.Uint("id", id),
+			zap.Uint("expected_version", expectedVersion),
+			zap.Uint("current_version", existing.Version))
+		return nil, versionConflictError(ctx)
+	}
 
-			if err := HandleShortLinkRedisHllBackup(&existing); err != nil {
-				logging.Logger.Error("禁用时清理 Redis 缓存失败",
-					zap.Uint("id", existing.ID),
-					zap.Error(err))
-				return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
-			}
+	updates := map[string]interface{}{}
 
-			if err := HandleShortLinkRedisCleanup(&existing); err != nil {
-				logging.Logger.Error("禁用时清理 Redis 缓存失败",
-					zap.Uint("id", existing.ID),
-					zap.Error(err))
-				return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
-			}
-		} else {
-			// 由禁用变为启用，恢复 Redis 缓存
-			if err := RestoreShortLinkCacheFromDB(&existing); err != nil {
-				logging.Logger.Error("启用时恢复 Redis 缓存失败",
-					zap.Uint("id", existing.ID),
-					zap.Error(err))
-				return apperrors.SystemError(i18n.T(ctx, "error.redis_restore_failed", nil))
-			}
-		}
-		// 更新状态字段
-		existing.Disabled = *newDisabled
-	}
+	// 目标配置变更时需要记录新版本
+	targetChanged := existing.TargetURL != targetUrl || existing.RedirectCode != redirectCode
 
 	// 更新 targetUrl（如果有变更）
 	if existing.TargetURL != targetUrl {
 		existing.TargetURL = targetUrl
+		updates["target_url"] = targetUrl
 	}
 
 	if existing.RedirectCode != redirectCode {
 		existing.RedirectCode = redirectCode
+		updates["redirect_code"] = redirectCode
 	}
 
-	existing.UpdatedAt = time.Now()
+	// 判断状态是否需要变更
+	if newDisabled != nil && *newDisabled != existing.Disabled {
+		if err := saveShortLinkChangesWithStatus(ctx, &existing, *newDisabled, expectedVersion, updates, targetChanged, nil); err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
 
-	// 保存更新
-	if err := repository.DB.Save(&existing).Error; err != nil {
-		logging.Logger.Error("更新短链失败",
+	if err := saveShortLinkChanges(ctx, &existing, expectedVersion, updates, targetChanged, nil); err != nil {
+		return nil, err
+	}
+
+	return &existing, nil
+}
+
+// PatchShortLink 按 JSON Merge Patch 语义部分更新短链，仅修改请求体中出现的字段
+func PatchShortLink(ctx context.Context, id uint, req dto.PatchShortLinkRequest, expectedVersion uint) (*model.ShortLink, error) {
+	if err := req.Validate(); err != nil {
+		message := i18n.T(ctx, err.Error(), nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	existing, err := GetShortLink(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 在产生任何副作用之前先校验版本号
+	if expectedVersion == 0 {
+		expectedVersion = existing.Version
+	}
+	if existing.Version != expectedVersion {
+		logging.Logger.Info("短链版本冲突",
 			zap.Uint("id", id),
-			zap.String("target_url", targetUrl),
+			zap.Uint("expected_version", expectedVersion),
+			zap.Uint("current_version", existing.Version))
+		return nil, versionConflictError(ctx)
+	}
+
+	updates := map[string]interface{}{}
+	targetChanged := false
+
+	if req.TargetURL.Set && req.TargetURL.Value != existing.TargetURL {
+		existing.TargetURL = req.TargetURL.Value
+		updates["target_url"] = existing.TargetURL
+		targetChanged = true
+	}
+
+	if req.RedirectCode.Set {
+		redirectCode := http.StatusFound
+		if !req.RedirectCode.Null {
+			redirectCode = req.RedirectCode.Value
+		}
+		if redirectCode != existing.RedirectCode {
+			existing.RedirectCode = redirectCode
+			updates["redirect_code"] = redirectCode
+			targetChanged = true
+		}
+	}
+
+	if req.RedirectMode.Set {
+		redirectMode := model.RedirectModeHTTP
+		if !req.RedirectMode.Null {
+			redirectMode = req.RedirectMode.Value
+		}
+		if redirectMode != existing.RedirectMode {
+			existing.RedirectMode = redirectMode
+			updates["redirect_mode"] = redirectMode
+			targetChanged = true
+		}
+	}
+
+	if req.ExpiresAt.Set {
+		if req.ExpiresAt.Null {
+			existing.ExpiresAt = nil
+		} else {
+			existing.ExpiresAt = &req.ExpiresAt.Value
+		}
+		updates["expires_at"] = existing.ExpiresAt
+	}
+
+	if req.Description.Set {
+		existing.Description = req.Description.Value
+		updates["description"] = existing.Description
+	}
+
+	if req.Notes.Set {
+		existing.Notes = req.Notes.Value
+		updates["notes"] = existing.Notes
+	}
+
+	// UTM、预览信息与透传开关同属跳转规则，变更时同样记录新版本
+	if req.UTM.Set && req.UTM.Value != existing.UTM {
+		existing.UTM = req.UTM.Value // null 时为零值，即清空全部 UTM 参数
+		for column, value := range existing.UTM.Values() {
+			updates[column] = value
+		}
+		targetChanged = true
+	}
+
+	if req.OpenGraph.Set && req.OpenGraph.Value != existing.OpenGraph {
+		existing.OpenGraph = req.OpenGraph.Value // null 时为零值，即清空预览信息
+		for column, value := range existing.OpenGraph.Values() {
+			updates[column] = value
+		}
+		targetChanged = true
+	}
+
+	if req.QueryPassthrough.Set && req.QueryPassthrough.Value != existing.QueryPassthrough {
+		existing.QueryPassthrough = req.QueryPassthrough.Value
+		updates["query_passthrough"] = existing.QueryPassthrough
+		targetChanged = true
+	}
+
+	if req.PathPassthrough.Set && req.PathPassthrough.Value != existing.PathPassthrough {
+		existing.PathPassthrough = req.PathPassthrough.Value
+		updates["path_passthrough"] = existing.PathPassthrough
+		targetChanged = true
+	}
+
+	if req.InternalCIDRs.Set {
+		existing.InternalCIDRs = normalizeCIDRs(req.InternalCIDRs.Value) // null 时清空
+		updates["internal_cidrs"] = existing.InternalCIDRs
+	}
+
+	var afterUpdate func(tx *gorm.DB) error
+	if req.Tags.Set {
+		afterUpdate = func(tx *gorm.DB) error {
+			return replaceShortLinkTags(tx, existing, req.Tags.Value)
+		}
+	}
+
+	// 请求体中没有任何字段需要修改
+	disabledChanged := req.Disabled.Set && req.Disabled.Value != existing.Disabled
+	if len(updates) == 0 && afterUpdate == nil && !disabledChanged {
+		return existing, nil
+	}
+
+	if disabledChanged {
+		if err := saveShortLinkChangesWithStatus(ctx, existing, req.Disabled.Value, expectedVersion, updates, targetChanged, afterUpdate); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	if err := saveShortLinkChanges(ctx, existing, expectedVersion, updates, targetChanged, afterUpdate); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// applyDisabledChange 处理启用/禁用状态切换的副作用（同步统计、备份/恢复 HLL、清理 Redis）
+func applyDisabledChange(ctx context.Context, existing *model.ShortLink, disabled bool) error {
+	if disabled {
+		// 禁用：同步统计、备份、清理 Redis
+		if err := DoStatisticalData(existing, constant.GetDateKey()); err != nil {
+			logging.Logger.Error("禁用时同步统计数据失败",
+				zap.Uint("id", existing.ID),
+				zap.Error(err))
+			return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+		}
+
+		if err := HandleShortLinkRedisHllBackup(existing); err != nil {
+			logging.Logger.Error("禁用时清理 Redis 缓存失败",
+				zap.Uint("id", existing.ID),
+				zap.Error(err))
+			return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+		}
+
+		if err := HandleShortLinkRedisCleanup(existing); err != nil {
+			logging.Logger.Error("禁用时清理 Redis 缓存失败",
+				zap.Uint("id", existing.ID),
+				zap.Error(err))
+			return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+		}
+		return nil
+	}
+
+	// 由禁用变为启用，恢复 Redis 缓存
+	if err := RestoreShortLinkCacheFromDB(existing); err != nil {
+		logging.Logger.Error("启用时恢复 Redis 缓存失败",
+			zap.Uint("id", existing.ID),
+			zap.Error(err))
+		return apperrors.SystemError(i18n.T(ctx, "error.redis_restore_failed", nil))
+	}
+	return nil
+}
+
+// saveShortLinkChanges 按列更新短链（带版本号条件），不会覆盖定时任务写入的 total_pv/total_uv
+// afterUpdate 可选，在同一事务中执行关联数据（如标签）的更新
+func saveShortLinkChanges(
+	ctx context.Context,
+	existing *model.ShortLink,
+	expectedVersion uint,
+	updates map[string]interface{},
+	targetChanged bool,
+	afterUpdate func(tx *gorm.DB) error,
+) error {
+	now := time.Now()
+	updates["updated_at"] = now
+	updates["version"] = gorm.Expr("version + 1")
+	if targetURL, ok := updates["target_url"].(string); ok {
+		existing.CanonicalHash = canonicalTargetHash(targetURL)
+		updates["canonical_hash"] = existing.CanonicalHash
+	}
+
+	if err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if targetChanged {
+			if err := seedShortLinkRevision(tx, existing.ID); err != nil {
+				return err
+			}
+		}
+		result := tx.Model(&model.ShortLink{}).
+			Where("id = ? AND version = ?", existing.ID, expectedVersion).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return versionConflictError(ctx)
+		}
+		if targetChanged {
+			if err := recordShortLinkRevision(tx, existing); err != nil {
+				return err
+			}
+		}
+		if afterUpdate != nil {
+			return afterUpdate(tx)
+		}
+		return nil
+	}); err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		logging.Logger.Error("更新短链失败",
+			zap.Uint("id", existing.ID),
+			zap.String("target_url", existing.TargetURL),
 			zap.Bool("disabled", existing.Disabled),
 			zap.Error(err))
 		message := i18n.T(ctx, "error.system_error", nil)
 		return apperrors.SystemError(message)
 	}
 
+	existing.UpdatedAt = now
+	existing.Version = expectedVersion + 1
+	existing.FinalURL = existing.FinalTargetURL()
+
+	// 使 Redis 中的短链缓存失效，下次访问时从数据库重新加载
+	if err := InvalidateShortLinkCache(existing); err != nil {
+		logging.Logger.Warn("清理短链缓存失败",
+			zap.Uint("id", existing.ID),
+			zap.String("shortcode", existing.ShortCode),
+			zap.Error(err))
+	}
+
+	return nil
+}
+
+// saveShortLinkChangesWithStatus 切换启用/禁用状态并保存其他修改：先处理 Redis 副作用（禁用前需同步统计），
+// 带版本号的写库失败（包括并发修改导致的版本冲突）时撤销已执行的副作用，与 DeleteShortLink 的处理方式一致
+func saveShortLinkChangesWithStatus(
+	ctx context.Context,
+	existing *model.ShortLink,
+	disabled bool,
+	expectedVersion uint,
+	updates map[string]interface{},
+	targetChanged bool,
+	afterUpdate func(tx *gorm.DB) error,
+) error {
+	previous := existing.Disabled
+	if err := applyDisabledChange(ctx, existing, disabled); err != nil {
+		return err
+	}
+	existing.Disabled = disabled
+	updates["disabled"] = disabled
+
+	if err := saveShortLinkChanges(ctx, existing, expectedVersion, updates, targetChanged, afterUpdate); err != nil {
+		existing.Disabled = previous
+		if undoErr := applyDisabledChange(ctx, existing, previous); undoErr != nil {
+			logging.Logger.Error("撤销状态切换的 Redis 副作用失败",
+				zap.Uint("id", existing.ID),
+				zap.Bool("disabled", previous),
+				zap.Error(undoErr))
+		}
+		return err
+	}
 	return nil
 }
 
-func RedirectToTargetURL(shortCode string, ip string) (*model.ShortLink, bool) {
+// ErrVersionConflict 乐观锁版本号不一致，可通过 errors.Is 与其他 409 错误（如短码已存在）区分
+var ErrVersionConflict = errors.New("short link version conflict")
+
+// versionConflictError 短链已被其他请求修改
+func versionConflictError(ctx context.Context) *apperrors.AppError {
+	message := i18n.T(ctx, "error.version_conflict", nil)
+	appErr := apperrors.BusinessError(http.StatusConflict, message)
+	appErr.Cause = ErrVersionConflict
+	return appErr
+}
+
+// RedirectToTargetURL 根据短码（或别名）查询可跳转的短链，优先读取 Redis 缓存
+func RedirectToTargetURL(shortCode string) (*model.ShortLink, bool) {
 	if err := utils.ValidateShortCode(shortCode); err != nil {
 		logging.Logger.Error("无效的 short_code",
 			zap.String("short_code", shortCode),         // 出错的 short_code
@@ -227,7 +624,11 @@ func RedirectToTargetURL(shortCode string, ip string) (*model.ShortLink, bool) {
 		)
 		return nil, false
 	}
+	return lookupShortLink(shortCode, true)
+}
 
+// lookupShortLink 按已校验的短码查询短链，优先读取 Redis 缓存；cacheMiss 为 false 时未找到不写入空值缓存
+func lookupShortLink(shortCode string, cacheMiss bool) (*model.ShortLink, bool) {
 	cacheKey := constant.GetShortCodeKey(shortCode)
 
 	conn := repository.RedisPool.Get()
@@ -249,6 +650,9 @@ func RedirectToTargetURL(shortCode string, ip string) (*model.ShortLink, bool) {
 	if err == nil {
 		var shortLink model.ShortLink
 		if err := json.Unmarshal(cachedValue, &shortLink); err == nil {
+			if shortLink.IsExpired(time.Now()) {
+				return nil, false
+			}
 			return &shortLink, true
 		} else if string(cachedValue) == "" {
 			return nil, false
@@ -264,9 +668,11 @@ func RedirectToTargetURL(shortCode string, ip string) (*model.ShortLink, bool) {
 	}
 
 	// 缓存未命中，从数据库查询
-	var shortLink model.ShortLink
-	result := repository.DB.Where("short_code = ? AND disabled = ?", shortCode, false).First(&shortLink)
-	if result.Error != nil {
+	shortLink, err := findActiveShortLinkByCode(shortCode)
+	if err != nil {
+		if !cacheMiss {
+			return nil, false
+		}
 		// 缓存空值，防止缓存穿透
 		_, err := conn.Do("SET", cacheKey, "", "EX", 300)
 		if err != nil {
@@ -278,10 +684,17 @@ func RedirectToTargetURL(shortCode string, ip string) (*model.ShortLink, bool) {
 		return nil, false
 	}
 
-	// 缓存结果（1小时）
+	// 缓存结果（1小时，不超过过期时间）
 	cachedValue, _ = json.Marshal(shortLink)
 
-	_, err = conn.Do("SET", cacheKey, cachedValue, "EX", 3600)
+	ttl := 3600
+	if shortLink.ExpiresAt != nil {
+		if remaining := int(time.Until(*shortLink.ExpiresAt).Seconds()); remaining < ttl {
+			ttl = max(remaining, 1)
+		}
+	}
+
+	_, err = conn.Do("SET", cacheKey, cachedValue, "EX", ttl)
 	if err != nil {
 		// 记录日志或者做其他错误处理
 		logging.Logger.Error("设置缓存失败",
@@ -291,13 +704,70 @@ func RedirectToTargetURL(shortCode string, ip string) (*model.ShortLink, bool) {
 		)
 	}
 
-	RecordDailyPV(conn, shortCode)
-	RecordDailyUV(conn, shortCode, ip)
-	RecordTotalPV(conn, shortCode)
-	RecordTotalUV(conn, shortCode, ip)
+	return shortLink, true
 
-	return &shortLink, true
+}
 
+// findActiveShortLinkByCode 按短码查询未禁用、未过期的短链，找不到时再按别名查询
+func findActiveShortLinkByCode(code string) (*model.ShortLink, error) {
+	active := func() *gorm.DB {
+		return repository.DB.
+			Where("disabled = ?", false).
+			Where("expires_at IS NULL OR expires_at > ?", time.Now())
+	}
+
+	var shortLink model.ShortLink
+	err := active().Where("short_code = ?", code).First(&shortLink).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var alias model.ShortLinkAlias
+		if aliasErr := repository.DB.Where("code = ?", code).First(&alias).Error; aliasErr != nil {
+			return nil, err
+		}
+		err = active().First(&shortLink, alias.ShortLinkID).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shortLink, nil
+}
+
+// isShortCodeTaken 判断短码是否已被短链或别名占用，excludeLinkID 所属的别名不计入
+func isShortCodeTaken(db *gorm.DB, code string, excludeLinkID uint) (bool, error) {
+	var count int64
+	// 回收站中的短链仍占用短码
+	if err := db.Unscoped().Model(&model.ShortLink{}).
+		Where("short_code = ?", code).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := db.Model(&model.ShortLinkAlias{}).
+		Where("code = ? AND short_link_id <> ?", code, excludeLinkID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// shortLinkCacheKeys 返回短链及其所有别名的跳转缓存 key
+func shortLinkCacheKeys(shortLink *model.ShortLink) []string {
+	keys := []string{constant.GetShortCodeKey(shortLink.ShortCode)}
+
+	var aliasCodes []string
+	if err := repository.DB.Model(&model.ShortLinkAlias{}).
+		Where("short_link_id = ?", shortLink.ID).
+		Pluck("code", &aliasCodes).Error; err != nil {
+		logging.Logger.Warn("查询短链别名失败",
+			zap.Uint("id", shortLink.ID),
+			zap.Error(err))
+	}
+	for _, code := range aliasCodes {
+		keys = append(keys, constant.GetShortCodeKey(code))
+	}
+	return keys
 }
 
 func StatisticalData() error {
@@ -322,53 +792,49 @@ func StatisticalData() error {
 	return nil
 }
 
+// DeleteShortLink 软删除短链：先同步统计、备份 UV 并清理 Redis，再移入回收站（短码仍被占用）
 func DeleteShortLink(ctx context.Context, id uint) error {
-	return repository.DB.Transaction(func(tx *gorm.DB) error {
-		// 查询现有短链记录
-		var existing model.ShortLink
-		if err := tx.First(&existing, id).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil
-			}
-			logging.Logger.Error("查询短链失败",
-				zap.Uint("id", id),
-				zap.Error(err))
-			message := i18n.T(ctx, "error.system_error", nil)
-			return apperrors.SystemError(message)
-		}
-
-		// 删除 daily_stats 统计记录
-		if err := tx.Where("short_link_id = ?", existing.ID).Delete(&model.DailyStat{}).Error; err != nil {
-			logging.Logger.Error("删除 daily_stats 统计记录失败",
-				zap.Uint("id", existing.ID),
-				zap.Error(err))
-			return apperrors.SystemError(i18n.T(ctx, "error.daily_stats_delete_failed", nil))
-		}
-
-		// 删除 short_link 本身
-		if err := tx.Delete(&model.ShortLink{}, id).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				message := i18n.T(ctx, "error.shortcode_not_found", nil)
-				return apperrors.BusinessError(http.StatusNotFound, message)
-			}
-			logging.Logger.Error("删除短链失败",
-				zap.Uint("id", id),
-				zap.Error(err))
-			message := i18n.T(ctx, "error.system_error", nil)
-			return apperrors.SystemError(message)
+	var existing model.ShortLink
+	if err := repository.DB.First(&existing, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
 		}
+		logging.Logger.Error("查询短链失败",
+			zap.Uint("id", id),
+			zap.Error(err))
+		message := i18n.T(ctx, "error.system_error", nil)
+		return apperrors.SystemError(message)
+	}
 
-		// 清理 Redis 缓存
-		if err := HandleShortLinkRedisCleanup(&existing); err != nil {
-			logging.Logger.Error("禁用时清理 Redis 缓存失败",
+	if existing.Disabled {
+		// 禁用时已同步统计并清理 Redis，只需删除跳转缓存
+		if err := InvalidateShortLinkCache(&existing); err != nil {
+			logging.Logger.Error("删除时清理 Redis 缓存失败",
 				zap.Uint("id", existing.ID),
 				zap.String("shortcode", existing.ShortCode),
 				zap.Error(err))
 			return apperrors.SystemError(i18n.T(ctx, "error.redis_cleanup_failed", nil))
 		}
+	} else if err := applyDisabledChange(ctx, &existing, true); err != nil {
+		return err
+	}
+
+	if err := repository.DB.Delete(&existing).Error; err != nil {
+		logging.Logger.Error("删除短链失败",
+			zap.Uint("id", id),
+			zap.Error(err))
+		// 删除失败时恢复已清理的 Redis 计数
+		if !existing.Disabled {
+			_ = applyDisabledChange(ctx, &existing, false)
+		}
+		message := i18n.T(ctx, "error.system_error", nil)
+		return apperrors.SystemError(message)
+	}
 
-		return nil
-	})
+	logging.Logger.Info("短链已移入回收站",
+		zap.Uint("id", existing.ID),
+		zap.String("shortcode", existing.ShortCode))
+	return nil
 }
 
 func DoStatisticalData(shortLink *model.ShortLink, today string) error {
@@ -390,7 +856,15 @@ func DoStatisticalData(shortLink *model.ShortLink, today string) error {
 	shortLink.TotalPV = totalPv
 	shortLink.TotalUV = totalUv
 
-	return SaveStatisticalData(shortLink, today, dailyPv, dailyUv, totalPv, totalUv)
+	if err := SaveStatisticalData(shortLink, today, dailyPv, dailyUv, totalPv, totalUv); err != nil {
+		return err
+	}
+
+	if err := SaveExtraStatisticalData(shortLink); err != nil {
+		return err
+	}
+
+	return SaveAliasStatisticalData(shortLink)
 }
 
 func GetStatisticalData(shortLink model.ShortLink, today string) (dailyPv, dailyUv, totalPv, totalUv uint64, err error) {
@@ -456,6 +930,95 @@ func SaveStatisticalData(shortLink *model.ShortLink, today string, dailyPv, dail
 	return nil
 }
 
+// SaveExtraStatisticalData 同步机器流量、内部网络访问与拒绝跟踪访问的计数到数据库
+func SaveExtraStatisticalData(shortLink *model.ShortLink) error {
+	conn := repository.RedisPool.Get()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.Logger.Error("Failed to close Redis connection",
+				zap.Error(err),
+				zap.String("operation", "close"),
+				zap.String("connection_type", "redis"),
+			)
+		}
+	}()
+
+	botPv, err := GetBotPv(conn, shortLink.ShortCode)
+	if err != nil {
+		return err
+	}
+	internalPv, err := GetInternalPv(conn, shortLink.ShortCode)
+	if err != nil {
+		return err
+	}
+	optOutPv, err := GetOptOutPv(conn, shortLink.ShortCode)
+	if err != nil {
+		return err
+	}
+	shortLink.BotPV = botPv
+	shortLink.InternalPV = internalPv
+	shortLink.OptOutPV = optOutPv
+
+	if err := repository.DB.Model(&model.ShortLink{}).
+		Where("id = ?", shortLink.ID).
+		Updates(map[string]interface{}{
+			"bot_pv":      botPv,
+			"internal_pv": internalPv,
+			"opt_out_pv":  optOutPv,
+		}).Error; err != nil {
+		logging.Logger.Error("Failed to update bot/internal/opt-out PV", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// SaveAliasStatisticalData 同步短链各别名的 PV/UV 到数据库
+func SaveAliasStatisticalData(shortLink *model.ShortLink) error {
+	var aliases []model.ShortLinkAlias
+	if err := repository.DB.Where("short_link_id = ?", shortLink.ID).Find(&aliases).Error; err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	conn := repository.RedisPool.Get()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.Logger.Error("Failed to close Redis connection",
+				zap.Error(err),
+				zap.String("operation", "close"),
+				zap.String("connection_type", "redis"),
+			)
+		}
+	}()
+
+	for _, alias := range aliases {
+		aliasPv, err := GetAliasPv(conn, alias.Code)
+		if err != nil {
+			return err
+		}
+		aliasUv, err := GetAliasUv(conn, alias.Code)
+		if err != nil {
+			return err
+		}
+
+		if err := repository.DB.Model(&model.ShortLinkAlias{}).
+			Where("id = ?", alias.ID).
+			Updates(map[string]interface{}{
+				"total_pv": aliasPv,
+				"total_uv": aliasUv,
+			}).Error; err != nil {
+			logging.Logger.Error("Failed to update alias PV/UV",
+				zap.String("alias", alias.Code),
+				zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
 func HandleShortLinkRedisHllBackup(shortLink *model.ShortLink) error {
 	conn := repository.RedisPool.Get()
 	defer func() {
@@ -483,7 +1046,9 @@ func HandleShortLinkRedisHllBackup(shortLink *model.ShortLink) error {
 	}
 	shortLink.UvHLLBackup = hllData
 
-	if err := repository.DB.Save(shortLink).Error; err != nil {
+	if err := repository.DB.Model(&model.ShortLink{}).
+		Where("id = ?", shortLink.ID).
+		Update("uv_hll_backup", hllData).Error; err != nil {
 		logging.Logger.Error("保存 UV HyperLogLog 备份失败", zap.Error(err))
 		return err
 	}
@@ -505,10 +1070,11 @@ func HandleShortLinkRedisCleanup(shortLink *model.ShortLink) error {
 
 	shortcode := shortLink.ShortCode
 	totalUvKey := constant.GetTotalUVKey(shortcode)
-	cacheKey := constant.GetShortCodeKey(shortcode)
 	totalPvKey := constant.GetTotalPVKey(shortcode)
 
-	for _, key := range []string{cacheKey, totalPvKey, totalUvKey} {
+	keys := append(shortLinkCacheKeys(shortLink), totalPvKey, totalUvKey,
+		constant.GetBotPVKey(shortcode), constant.GetInternalPVKey(shortcode), constant.GetOptOutPVKey(shortcode))
+	for _, key := range keys {
 		if _, err := conn.Do("DEL", key); err != nil {
 			logging.Logger.Warn("删除 Redis 缓存失败", zap.String("key", key), zap.Error(err))
 			// return err
@@ -518,6 +1084,27 @@ func HandleShortLinkRedisCleanup(shortLink *model.ShortLink) error {
 	return nil
 }
 
+// InvalidateShortLinkCache 删除短链的跳转缓存（不影响 PV/UV 统计）
+func InvalidateShortLinkCache(shortLink *model.ShortLink) error {
+	conn := repository.RedisPool.Get()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.Logger.Error("关闭 Redis 连接失败",
+				zap.Error(err),
+				zap.String("operation", "close"),
+				zap.String("connection_type", "redis"),
+			)
+		}
+	}()
+
+	for _, cacheKey := range shortLinkCacheKeys(shortLink) {
+		if _, err := conn.Do("DEL", cacheKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RestoreShortLinkCacheFromDB 从数据库恢复 Redis 缓存（PV + UV）
 func RestoreShortLinkCacheFromDB(shortLink *model.ShortLink) error {
 	conn := repository.RedisPool.Get()
@@ -545,6 +1132,23 @@ func RestoreShortLinkCacheFromDB(shortLink *model.ShortLink) error {
 		}
 	}
 
+	// 恢复机器流量、内部网络访问与拒绝跟踪访问计数
+	for key, value := range map[string]uint64{
+		constant.GetBotPVKey(shortcode):      shortLink.BotPV,
+		constant.GetInternalPVKey(shortcode): shortLink.InternalPV,
+		constant.GetOptOutPVKey(shortcode):   shortLink.OptOutPV,
+	} {
+		if value == 0 {
+			continue
+		}
+		if _, err := conn.Do("SET", key, value); err != nil {
+			logging.Logger.Warn("恢复 Redis 计数失败",
+				zap.String("key", key),
+				zap.Uint64("value", value),
+				zap.Error(err))
+		}
+	}
+
 	// 恢复 UV HyperLogLog
 	if len(shortLink.UvHLLBackup) > 0 {
 		_, _ = conn.Do("DEL", totalUvKey)
@@ -556,3 +1160,18 @@ func RestoreShortLinkCacheFromDB(shortLink *model.ShortLink) error {
 	}
 	return nil
 }
+
+// PreviewTargetURL 预览合并 UTM 参数后的跳转地址（不保存）
+func PreviewTargetURL(ctx context.Context, req dto.PreviewTargetURLRequest) (string, error) {
+	if err := utils.ValidateTargetURL(req.TargetURL); err != nil {
+		message := i18n.T(ctx, err.Error(), nil)
+		return "", apperrors.InvalidRequestError(message)
+	}
+	if err := dto.ValidateUTM(req.UTM); err != nil {
+		message := i18n.T(ctx, err.Error(), nil)
+		return "", apperrors.InvalidRequestError(message)
+	}
+
+	link := model.ShortLink{TargetURL: req.TargetURL, UTM: req.UTM}
+	return link.FinalTargetURL(), nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/model"
+	"testing"
+	"time"
+)
+
+func TestSameRedirectSettings(t *testing.T) {
+	expiry := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	link := model.ShortLink{
+		RedirectCode: 302,
+		RedirectMode: model.RedirectModeHTTP,
+		ExpiresAt:    &expiry,
+		UTM:          model.UTMParams{Source: "newsletter"},
+	}
+	base := dto.CreateShortLinkRequest{
+		RedirectCode: 302,
+		RedirectMode: model.RedirectModeHTTP,
+		ExpiresAt:    &expiry,
+		UTM:          model.UTMParams{Source: "newsletter"},
+	}
+
+	sameExpiry := expiry.In(time.FixedZone("UTC+8", 8*3600))
+	otherExpiry := expiry.Add(time.Hour)
+	tests := []struct {
+		name   string
+		modify func(req *dto.CreateShortLinkRequest)
+		want   bool
+	}{
+		{"identical", func(*dto.CreateShortLinkRequest) {}, true},
+		{"same expiry in another zone", func(r *dto.CreateShortLinkRequest) { r.ExpiresAt = &sameExpiry }, true},
+		{"different redirect code", func(r *dto.CreateShortLinkRequest) { r.RedirectCode = 301 }, false},
+		{"different redirect mode", func(r *dto.CreateShortLinkRequest) { r.RedirectMode = model.RedirectModeMeta }, false},
+		{"different utm", func(r *dto.CreateShortLinkRequest) { r.UTM.Campaign = "spring" }, false},
+		{"different expiry", func(r *dto.CreateShortLinkRequest) { r.ExpiresAt = &otherExpiry }, false},
+		{"no expiry", func(r *dto.CreateShortLinkRequest) { r.ExpiresAt = nil }, false},
+		{"query passthrough", func(r *dto.CreateShortLinkRequest) { r.QueryPassthrough = true }, false},
+		{"path passthrough", func(r *dto.CreateShortLinkRequest) { r.PathPassthrough = true }, false},
+		{"open graph", func(r *dto.CreateShortLinkRequest) { r.OpenGraph.Title = "Sale" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := base
+			tt.modify(&req)
+			if got := sameRedirectSettings(&link, req); got != tt.want {
+				t.Errorf("sameRedirectSettings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
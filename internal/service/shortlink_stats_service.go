@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errInvalidDateRange = errors.New("invalid date range")
+
+// GetShortLinkStats 查询短链统计：总量、日期范围内的每日数据以及各别名的数据
+// from/to 格式为 yyyy-MM-dd，未传时默认最近 30 天
+func GetShortLinkStats(ctx context.Context, id uint, from, to string) (*dto.ShortLinkStatsResponse, error) {
+	fromDate, toDate, err := parseStatsDateRange(from, to)
+	if err != nil {
+		message := i18n.T(ctx, "error.date_range_invalid", nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	shortLink, err := GetShortLink(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	daily := make([]dto.DailyStatItem, 0)
+	if err := repository.DB.Model(&model.DailyStat{}).
+		Select("DATE_FORMAT(date, '%Y-%m-%d') AS date, pv, uv").
+		Where("short_link_id = ? AND date BETWEEN ? AND ?", id, fromDate, toDate).
+		Order("date ASC").
+		Scan(&daily).Error; err != nil {
+		logging.Logger.Error("查询每日统计失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	aliases := make([]dto.AliasStatItem, 0)
+	if err := repository.DB.Model(&model.ShortLinkAlias{}).
+		Select("code, total_pv, total_uv").
+		Where("short_link_id = ?", id).
+		Order("id ASC").
+		Scan(&aliases).Error; err != nil {
+		logging.Logger.Error("查询别名统计失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	return &dto.ShortLinkStatsResponse{
+		ID:         shortLink.ID,
+		ShortCode:  shortLink.ShortCode,
+		TotalPV:    shortLink.TotalPV,
+		TotalUV:    shortLink.TotalUV,
+		BotPV:      shortLink.BotPV,
+		InternalPV: shortLink.InternalPV,
+		OptOutPV:   shortLink.OptOutPV,
+		From:       fromDate,
+		To:         toDate,
+		Daily:      daily,
+		Aliases:    aliases,
+	}, nil
+}
+
+// parseStatsDateRange 解析统计日期范围，默认最近 30 天
+func parseStatsDateRange(from, to string) (string, string, error) {
+	const layout = "2006-01-02"
+
+	toTime := time.Now()
+	if to != "" {
+		parsed, err := time.ParseInLocation(layout, to, time.Local)
+		if err != nil {
+			return "", "", err
+		}
+		toTime = parsed
+	}
+
+	fromTime := toTime.AddDate(0, 0, -29)
+	if from != "" {
+		parsed, err := time.ParseInLocation(layout, from, time.Local)
+		if err != nil {
+			return "", "", err
+		}
+		fromTime = parsed
+	}
+
+	if fromTime.After(toTime) {
+		return "", "", errInvalidDateRange
+	}
+	return fromTime.Format(layout), toTime.Format(layout), nil
+}
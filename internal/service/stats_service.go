@@ -19,7 +19,7 @@ func RecordDailyPV(conn redis.Conn, shortCode string) {
 			zap.Error(err))
 	}
 
-	_, err = conn.Do("EXPIRE", dailyPvKey, 3*24*3600) // 3天过期
+	_, err = conn.Do("EXPIRE", dailyPvKey, constant.DailyKeyTTLDays*24*3600) // 3天过期
 	if err != nil {
 		logging.Logger.Error("Failed to record daily PV Expire",
 			zap.String("key", dailyPvKey),
@@ -28,20 +28,20 @@ func RecordDailyPV(conn redis.Conn, shortCode string) {
 	}
 }
 
-// RecordDailyUV 记录每日 UV
-func RecordDailyUV(conn redis.Conn, shortCode string, ip string) {
+// RecordDailyUV 记录每日 UV，visitorID 为按隐私策略处理后的访客标识
+func RecordDailyUV(conn redis.Conn, shortCode string, visitorID string) {
 
 	dailyUvKey := constant.GetDailyUVKey(shortCode, constant.GetDateKey())
 
-	_, err := conn.Do("PFADD", dailyUvKey, ip)
+	_, err := conn.Do("PFADD", dailyUvKey, visitorID)
 	if err != nil {
 		logging.Logger.Error("Failed to record daily UV",
 			zap.String("key", dailyUvKey),
-			zap.String("ip", ip),
+			zap.String("visitor_id", visitorID),
 			zap.Error(err))
 	}
 
-	_, err = conn.Do("EXPIRE", dailyUvKey, 3*24*3600) // 3天过期
+	_, err = conn.Do("EXPIRE", dailyUvKey, constant.DailyKeyTTLDays*24*3600) // 3天过期
 	if err != nil {
 		logging.Logger.Error("Failed to record daily UV Expire",
 			zap.String("key", dailyUvKey),
@@ -63,13 +63,37 @@ func RecordTotalPV(conn redis.Conn, shortCode string) {
 }
 
 // RecordTotalUV 记录总UV
-func RecordTotalUV(conn redis.Conn, shortCode string, ip string) {
+func RecordTotalUV(conn redis.Conn, shortCode string, visitorID string) {
 	totalUvKey := constant.GetTotalUVKey(shortCode)
-	_, err := conn.Do("PFADD", totalUvKey, ip)
+	_, err := conn.Do("PFADD", totalUvKey, visitorID)
 	if err != nil {
 		logging.Logger.Error("Failed to record total UV",
 			zap.String("key", totalUvKey),
-			zap.String("ip", ip),
+			zap.String("visitor_id", visitorID),
+			zap.Error(err))
+	}
+}
+
+// RecordAliasPV 记录通过别名访问的 PV
+func RecordAliasPV(conn redis.Conn, alias string) {
+	aliasPvKey := constant.GetAliasPVKey(alias)
+	_, err := conn.Do("INCR", aliasPvKey)
+	if err != nil {
+		logging.Logger.Error("Failed to record alias PV",
+			zap.String("key", aliasPvKey),
+			zap.String("alias", alias),
+			zap.Error(err))
+	}
+}
+
+// RecordAliasUV 记录通过别名访问的 UV
+func RecordAliasUV(conn redis.Conn, alias string, visitorID string) {
+	aliasUvKey := constant.GetAliasUVKey(alias)
+	_, err := conn.Do("PFADD", aliasUvKey, visitorID)
+	if err != nil {
+		logging.Logger.Error("Failed to record alias UV",
+			zap.String("key", aliasUvKey),
+			zap.String("visitor_id", visitorID),
 			zap.Error(err))
 	}
 }
@@ -194,3 +218,131 @@ func GetTotalUv(conn redis.Conn, shortCode string) (uint64, error) {
 
 	return result, nil
 }
+
+// GetAliasPv 获取别名的总访问量（PV）
+func GetAliasPv(conn redis.Conn, alias string) (uint64, error) {
+	aliasPvKey := constant.GetAliasPVKey(alias)
+
+	result, err := redis.Uint64(conn.Do("GET", aliasPvKey))
+	if err == redis.ErrNil {
+		return 0, nil
+	}
+	if err != nil {
+		logging.Logger.Error("Failed to get alias PV",
+			zap.String("key", aliasPvKey),
+			zap.String("alias", alias),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// GetAliasUv 获取别名的总独立访客数（UV）
+func GetAliasUv(conn redis.Conn, alias string) (uint64, error) {
+	aliasUvKey := constant.GetAliasUVKey(alias)
+
+	result, err := redis.Uint64(conn.Do("PFCount", aliasUvKey))
+	if err != nil {
+		logging.Logger.Error("Failed to get alias UV",
+			zap.String("key", aliasUvKey),
+			zap.String("alias", alias),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// RecordBotPV 记录机器流量（机器人、预取、链接扫描），与正常访问的 PV/UV 分开计数
+func RecordBotPV(conn redis.Conn, shortCode string) {
+	botPvKey := constant.GetBotPVKey(shortCode)
+	_, err := conn.Do("INCR", botPvKey)
+	if err != nil {
+		logging.Logger.Error("Failed to record bot PV",
+			zap.String("key", botPvKey),
+			zap.String("short_code", shortCode),
+			zap.Error(err))
+	}
+}
+
+// GetBotPv 获取短链接的机器流量总数
+func GetBotPv(conn redis.Conn, shortCode string) (uint64, error) {
+	botPvKey := constant.GetBotPVKey(shortCode)
+
+	result, err := redis.Uint64(conn.Do("GET", botPvKey))
+	if err == redis.ErrNil {
+		return 0, nil
+	}
+	if err != nil {
+		logging.Logger.Error("Failed to get bot PV",
+			zap.String("key", botPvKey),
+			zap.String("short_code", shortCode),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// RecordInternalPV 记录来自内部网络的访问，与外部访问的 PV/UV 分开计数
+func RecordInternalPV(conn redis.Conn, shortCode string) {
+	internalPvKey := constant.GetInternalPVKey(shortCode)
+	_, err := conn.Do("INCR", internalPvKey)
+	if err != nil {
+		logging.Logger.Error("Failed to record internal PV",
+			zap.String("key", internalPvKey),
+			zap.String("short_code", shortCode),
+			zap.Error(err))
+	}
+}
+
+// GetInternalPv 获取短链接来自内部网络的访问总数
+func GetInternalPv(conn redis.Conn, shortCode string) (uint64, error) {
+	internalPvKey := constant.GetInternalPVKey(shortCode)
+
+	result, err := redis.Uint64(conn.Do("GET", internalPvKey))
+	if err == redis.ErrNil {
+		return 0, nil
+	}
+	if err != nil {
+		logging.Logger.Error("Failed to get internal PV",
+			zap.String("key", internalPvKey),
+			zap.String("short_code", shortCode),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// RecordOptOutPV 记录携带 DNT/GPC 拒绝跟踪的访问次数（这些访问已计入 PV，但不计入 UV）
+func RecordOptOutPV(conn redis.Conn, shortCode string) {
+	optOutPvKey := constant.GetOptOutPVKey(shortCode)
+	_, err := conn.Do("INCR", optOutPvKey)
+	if err != nil {
+		logging.Logger.Error("Failed to record opt-out PV",
+			zap.String("key", optOutPvKey),
+			zap.String("short_code", shortCode),
+			zap.Error(err))
+	}
+}
+
+// GetOptOutPv 获取短链接拒绝跟踪的访问总数
+func GetOptOutPv(conn redis.Conn, shortCode string) (uint64, error) {
+	optOutPvKey := constant.GetOptOutPVKey(shortCode)
+
+	result, err := redis.Uint64(conn.Do("GET", optOutPvKey))
+	if err == redis.ErrNil {
+		return 0, nil
+	}
+	if err != nil {
+		logging.Logger.Error("Failed to get opt-out PV",
+			zap.String("key", optOutPvKey),
+			zap.String("short_code", shortCode),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return result, nil
+}
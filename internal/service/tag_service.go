@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// resolveTags 根据标签名查找或创建标签（去除首尾空格并去重，需在事务中调用）
+func resolveTags(tx *gorm.DB, names []string) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		var tag model.Tag
+		if err := tx.Where(model.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// replaceShortLinkTags 用给定的标签整体替换短链的标签（需在事务中调用）
+func replaceShortLinkTags(tx *gorm.DB, shortLink *model.ShortLink, names []string) error {
+	tags, err := resolveTags(tx, names)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(shortLink).Association("Tags").Replace(tags); err != nil {
+		return err
+	}
+	shortLink.Tags = tags
+	return nil
+}
+
+// ListTags 查询全部标签及其短链数量，name 不为空时模糊匹配
+func ListTags(ctx context.Context, name string) ([]dto.TagItem, error) {
+	db := repository.DB.Model(&model.Tag{}).
+		Select("tags.*, COUNT(short_links.id) AS link_count").
+		Joins("LEFT JOIN short_link_tags ON short_link_tags.tag_id = tags.id").
+		Joins("LEFT JOIN short_links ON short_links.id = short_link_tags.short_link_id AND short_links.deleted_at IS NULL").
+		Group("tags.id").
+		Order("tags.name ASC")
+	if name != "" {
+		db = db.Where("tags.name LIKE ?", "%"+escapeLike(name)+"%")
+	}
+
+	items := make([]dto.TagItem, 0)
+	if err := db.Scan(&items).Error; err != nil {
+		logging.Logger.Error("查询标签列表失败", zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	return items, nil
+}
+
+// CreateTag 创建标签
+func CreateTag(ctx context.Context, req dto.CreateTagRequest) (*model.Tag, error) {
+	if err := req.Validate(); err != nil {
+		message := i18n.T(ctx, err.Error(), nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	if err := ensureTagNameAvailable(ctx, req.Name, 0); err != nil {
+		return nil, err
+	}
+
+	tag := &model.Tag{Name: req.Name, Description: req.Description}
+	if err := repository.DB.Create(tag).Error; err != nil {
+		logging.Logger.Error("创建标签失败", zap.String("name", req.Name), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	return tag, nil
+}
+
+// UpdateTag 修改标签名称或说明（改名对所有关联短链立即生效）
+func UpdateTag(ctx context.Context, id uint, req dto.UpdateTagRequest) (*model.Tag, error) {
+	if err := req.Validate(); err != nil {
+		message := i18n.T(ctx, err.Error(), nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	tag, err := getTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil && *req.Name != tag.Name {
+		if err := ensureTagNameAvailable(ctx, *req.Name, tag.ID); err != nil {
+			return nil, err
+		}
+		tag.Name = *req.Name
+		updates["name"] = tag.Name
+	}
+	if req.Description != nil && *req.Description != tag.Description {
+		tag.Description = *req.Description
+		updates["description"] = tag.Description
+	}
+	if len(updates) == 0 {
+		return tag, nil
+	}
+
+	if err := repository.DB.Model(tag).Updates(updates).Error; err != nil {
+		logging.Logger.Error("修改标签失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	return tag, nil
+}
+
+// DeleteTag 删除标签及其与短链的关联（短链本身不受影响）
+func DeleteTag(ctx context.Context, id uint) error {
+	if err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Exec("DELETE FROM short_link_tags WHERE tag_id = ?", id).Error
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			message := i18n.T(ctx, "error.tag_not_found", nil)
+			return apperrors.BusinessError(http.StatusNotFound, message)
+		}
+		logging.Logger.Error("删除标签失败", zap.Uint("id", id), zap.Error(err))
+		return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	return nil
+}
+
+// GetTagStats 汇总标签下所有短链（不含回收站）的访问数据
+func GetTagStats(ctx context.Context, id uint, from, to string) (*dto.TagStatsResponse, error) {
+	fromDate, toDate, err := parseStatsDateRange(from, to)
+	if err != nil {
+		message := i18n.T(ctx, "error.date_range_invalid", nil)
+		return nil, apperrors.InvalidRequestError(message)
+	}
+
+	tag, err := getTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var totals struct {
+		LinkCount int64
+		TotalPV   uint64
+		TotalUV   uint64
+	}
+	if err := repository.DB.Model(&model.ShortLink{}).
+		Select("COUNT(*) AS link_count, COALESCE(SUM(short_links.total_pv), 0) AS total_pv, "+
+			"COALESCE(SUM(short_links.total_uv), 0) AS total_uv").
+		Joins("JOIN short_link_tags ON short_link_tags.short_link_id = short_links.id").
+		Where("short_link_tags.tag_id = ?", id).
+		Scan(&totals).Error; err != nil {
+		logging.Logger.Error("查询标签汇总统计失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	daily := make([]dto.DailyStatItem, 0)
+	if err := repository.DB.Model(&model.DailyStat{}).
+		Select("DATE_FORMAT(daily_stats.date, '%Y-%m-%d') AS date, SUM(daily_stats.pv) AS pv, SUM(daily_stats.uv) AS uv").
+		Joins("JOIN short_link_tags ON short_link_tags.short_link_id = daily_stats.short_link_id").
+		Joins("JOIN short_links ON short_links.id = daily_stats.short_link_id AND short_links.deleted_at IS NULL").
+		Where("short_link_tags.tag_id = ? AND daily_stats.date BETWEEN ? AND ?", id, fromDate, toDate).
+		Group("daily_stats.date").
+		Order("daily_stats.date ASC").
+		Scan(&daily).Error; err != nil {
+		logging.Logger.Error("查询标签每日统计失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	return &dto.TagStatsResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		LinkCount: totals.LinkCount,
+		TotalPV:   totals.TotalPV,
+		TotalUV:   totals.TotalUV,
+		From:      fromDate,
+		To:        toDate,
+		Daily:     daily,
+	}, nil
+}
+
+func getTag(ctx context.Context, id uint) (*model.Tag, error) {
+	var tag model.Tag
+	if err := repository.DB.First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			message := i18n.T(ctx, "error.tag_not_found", nil)
+			return nil, apperrors.BusinessError(http.StatusNotFound, message)
+		}
+		logging.Logger.Error("查询标签失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	return &tag, nil
+}
+
+// ensureTagNameAvailable 标签名唯一，excludeID 为正在修改的标签
+func ensureTagNameAvailable(ctx context.Context, name string, excludeID uint) error {
+	var count int64
+	if err := repository.DB.Model(&model.Tag{}).
+		Where("name = ? AND id <> ?", name, excludeID).
+		Count(&count).Error; err != nil {
+		logging.Logger.Error("查询标签失败", zap.String("name", name), zap.Error(err))
+		return apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	if count > 0 {
+		message := i18n.T(ctx, "error.tag_exists", nil)
+		return apperrors.BusinessError(http.StatusConflict, message)
+	}
+	return nil
+}
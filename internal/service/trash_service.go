@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"net/http"
+	"shortlink-go/internal/apperrors"
+	"shortlink-go/internal/dto"
+	"shortlink-go/internal/i18n"
+	"shortlink-go/internal/model"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/response"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// defaultTrashRetentionDays 回收站默认保留天数
+const defaultTrashRetentionDays = 30
+
+// trashRetention 回收站保留时长（shortlink.trash_retention_days）
+func trashRetention() time.Duration {
+	days := viper.GetInt("shortlink.trash_retention_days")
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// ListTrashedShortLinks 分页查询回收站中的短链（按删除时间倒序）
+func ListTrashedShortLinks(ctx context.Context, page, size int) (*response.PageResponse[dto.TrashedShortLink], error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 10
+	}
+
+	db := repository.DB.Unscoped().Model(&model.ShortLink{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		logging.Logger.Error("统计回收站短链数失败", zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	var links []model.ShortLink
+	if total > 0 {
+		if err := db.
+			Preload("Tags").
+			Limit(size).
+			Offset((page - 1) * size).
+			Order("deleted_at DESC").
+			Find(&links).Error; err != nil {
+			logging.Logger.Error("分页查询回收站短链失败", zap.Error(err))
+			return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+		}
+	}
+
+	retention := trashRetention()
+	list := make([]dto.TrashedShortLink, 0, len(links))
+	for _, link := range links {
+		list = append(list, dto.TrashedShortLink{
+			ShortLink: link,
+			PurgeAt:   link.DeletedAt.Time.Add(retention),
+		})
+	}
+
+	return &response.PageResponse[dto.TrashedShortLink]{
+		Page:      page,
+		Size:      size,
+		Total:     int(total),
+		TotalPage: (int(total) + size - 1) / size,
+		List:      list,
+	}, nil
+}
+
+// RestoreShortLink 从回收站恢复短链，并从数据库恢复 Redis 中的 PV/UV 计数
+func RestoreShortLink(ctx context.Context, id uint) (*model.ShortLink, error) {
+	var existing model.ShortLink
+	if err := repository.DB.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&existing, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			message := i18n.T(ctx, "error.trash_not_found", nil)
+			return nil, apperrors.BusinessError(http.StatusNotFound, message)
+		}
+		logging.Logger.Error("查询回收站短链失败", zap.Uint("id", id), zap.Error(err))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+
+	if time.Since(existing.DeletedAt.Time) > trashRetention() {
+		message := i18n.T(ctx, "error.trash_retention_expired", nil)
+		return nil, apperrors.BusinessError(http.StatusGone, message)
+	}
+
+	now := time.Now()
+	result := repository.DB.Unscoped().Model(&model.ShortLink{}).
+		Where("id = ? AND version = ?", existing.ID, existing.Version).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_at": now,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		logging.Logger.Error("恢复短链失败", zap.Uint("id", id), zap.Error(result.Error))
+		return nil, apperrors.SystemError(i18n.T(ctx, "error.system_error", nil))
+	}
+	if result.RowsAffected == 0 {
+		return nil, versionConflictError(ctx)
+	}
+
+	existing.DeletedAt = gorm.DeletedAt{}
+	existing.UpdatedAt = now
+	existing.Version++
+
+	// 删除期间可能写入了空值缓存
+	if err := InvalidateShortLinkCache(&existing); err != nil {
+		logging.Logger.Warn("恢复时清理跳转缓存失败", zap.Uint("id", existing.ID), zap.Error(err))
+	}
+	if !existing.Disabled {
+		if err := applyDisabledChange(ctx, &existing, false); err != nil {
+			return nil, err
+		}
+	}
+
+	logging.Logger.Info("短链已从回收站恢复",
+		zap.Uint("id", existing.ID),
+		zap.String("shortcode", existing.ShortCode))
+	return &existing, nil
+}
+
+// PurgeExpiredTrash 彻底删除超过保留期的回收站短链及其统计、历史版本、别名和标签关联
+func PurgeExpiredTrash() error {
+	logging.Logger.Info("#PurgeExpiredTrash | start")
+
+	cutoff := time.Now().Add(-trashRetention())
+	var links []model.ShortLink
+	if err := repository.DB.Unscoped().
+		Select("id", "short_code").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Find(&links).Error; err != nil {
+		logging.Logger.Error("查询过期回收站短链失败", zap.Error(err))
+		return err
+	}
+
+	for i := range links {
+		if err := purgeShortLink(&links[i]); err != nil {
+			logging.Logger.Error("彻底删除短链失败",
+				zap.Uint("id", links[i].ID),
+				zap.String("shortcode", links[i].ShortCode),
+				zap.Error(err))
+		}
+	}
+
+	logging.Logger.Info("#PurgeExpiredTrash | end", zap.Int("count", len(links)))
+	return nil
+}
+
+// purgeShortLink 硬删除单条短链（Redis 计数已在软删除时清理，这里只清理别名相关 key）
+func purgeShortLink(shortLink *model.ShortLink) error {
+	var aliasCodes []string
+	if err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ShortLinkAlias{}).
+			Where("short_link_id = ?", shortLink.ID).
+			Pluck("code", &aliasCodes).Error; err != nil {
+			return err
+		}
+
+		for _, dependent := range []interface{}{
+			&model.DailyStat{}, &model.ShortLinkRevision{}, &model.ShortLinkAlias{},
+		} {
+			if err := tx.Where("short_link_id = ?", shortLink.ID).Delete(dependent).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM short_link_tags WHERE short_link_id = ?", shortLink.ID).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.ShortLink{}, shortLink.ID).Error
+	}); err != nil {
+		return err
+	}
+
+	for _, code := range aliasCodes {
+		cleanupAliasRedisKeys(code, true)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"shortlink-go/constant"
+	"shortlink-go/internal/repository"
+	"shortlink-go/pkg/logging"
+	"shortlink-go/pkg/visitorid"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// 访客标识策略（privacy.visitor_id），决定写入 UV HyperLogLog 的内容
+const (
+	VisitorIDIP       = "ip"       // 原始 IP（默认，兼容历史数据）
+	VisitorIDHash     = "hash"     // IP + User-Agent 的 HMAC，salt 每天轮换，只能按天去重
+	VisitorIDTruncate = "truncate" // 截断后的网段（IPv4 /24，IPv6 /48）
+	VisitorIDCookie   = "cookie"   // 第一方 Cookie 中的随机标识
+)
+
+// visitorSaltTTL 每日 salt 在 Redis 中的保留时间，过期后当天的访客标识无法再与 IP 关联
+const visitorSaltTTL = 2 * 24 * 3600
+
+// visitorSaltRetryInterval Redis 不可用时使用进程内 salt 的时长，到期后重新尝试读取共享 salt
+const visitorSaltRetryInterval = time.Minute
+
+var (
+	visitorSaltMu   sync.Mutex
+	visitorSaltDate string
+	visitorSalt     []byte
+
+	// 进程内 salt 只在 Redis 不可用时临时使用，不会覆盖从 Redis 读取的共享 salt
+	fallbackSaltDate  string
+	fallbackSalt      []byte
+	fallbackSaltRetry time.Time
+)
+
+// errEmptyVisitorSalt Redis 中的 salt 为空
+var errEmptyVisitorSalt = errors.New("visitor salt is empty")
+
+// VisitorIDStrategy 返回配置的访客标识策略，无法识别时使用原始 IP
+func VisitorIDStrategy() string {
+	switch strategy := viper.GetString("privacy.visitor_id"); strategy {
+	case VisitorIDHash, VisitorIDTruncate, VisitorIDCookie:
+		return strategy
+	default:
+		return VisitorIDIP
+	}
+}
+
+// VisitorCookieName 访客标识 Cookie 的名称
+func VisitorCookieName() string {
+	if name := viper.GetString("privacy.cookie_name"); name != "" {
+		return name
+	}
+	return "slvid"
+}
+
+// VisitorCookieMaxAge 访客标识 Cookie 的有效期（秒）
+func VisitorCookieMaxAge() int {
+	days := viper.GetInt("privacy.cookie_max_age_days")
+	if days <= 0 {
+		days = 365
+	}
+	return days * 24 * 3600
+}
+
+// IsTrackingOptedOut 判断访客是否通过 DNT: 1（privacy.respect_dnt）或 Sec-GPC: 1（privacy.respect_gpc）拒绝跟踪
+func IsTrackingOptedOut(r *http.Request) bool {
+	if viper.GetBool("privacy.respect_dnt") && strings.TrimSpace(r.Header.Get("DNT")) == "1" {
+		return true
+	}
+	return viper.GetBool("privacy.respect_gpc") && strings.TrimSpace(r.Header.Get("Sec-GPC")) == "1"
+}
+
+// AnonymizeLogs 是否在日志中隐藏客户端 IP（privacy.anonymize_logs）
+func AnonymizeLogs() bool {
+	return viper.GetBool("privacy.anonymize_logs")
+}
+
+// TruncateIP 按 privacy.ipv4_prefix / privacy.ipv6_prefix 截断 IP，用于访客标识与日志脱敏
+func TruncateIP(ip string) string {
+	v4Bits, v6Bits := visitorid.DefaultIPv4Bits, visitorid.DefaultIPv6Bits
+	if viper.IsSet("privacy.ipv4_prefix") {
+		v4Bits = viper.GetInt("privacy.ipv4_prefix")
+	}
+	if viper.IsSet("privacy.ipv6_prefix") {
+		v6Bits = viper.GetInt("privacy.ipv6_prefix")
+	}
+	return visitorid.TruncateIP(ip, v4Bits, v6Bits)
+}
+
+// HashVisitorID 用当天的 salt 计算 IP 与 User-Agent 的访客标识
+// salt 每天轮换，同一访客每天得到不同的标识，因此总 UV 统计的是各天 UV 之和（跨天重复访问会重复计数）
+func HashVisitorID(ip, userAgent string) (string, error) {
+	salt, err := dailyVisitorSalt()
+	if err != nil {
+		return "", err
+	}
+	return visitorid.Hash(salt, ip, userAgent), nil
+}
+
+// HashClientIP 用当天的 salt 计算客户端 IP 的 HMAC，供限流等需要按 IP 区分但不应在 Redis 中保存原始 IP 的场景使用
+// salt 每天轮换，轮换时限流窗口随之重置
+func HashClientIP(ip string) (string, error) {
+	salt, err := dailyVisitorSalt()
+	if err != nil {
+		return "", err
+	}
+	return visitorid.Hash(salt, ip, ""), nil
+}
+
+// dailyVisitorSalt 返回当天的 salt：多实例通过 Redis SET NX 共享同一个 salt
+// Redis 不可用时临时使用进程内随机 salt，并在 visitorSaltRetryInterval 后重新读取共享 salt
+func dailyVisitorSalt() ([]byte, error) {
+	today := constant.GetDateKey()
+
+	visitorSaltMu.Lock()
+	defer visitorSaltMu.Unlock()
+	if visitorSaltDate == today && visitorSalt != nil {
+		return visitorSalt, nil
+	}
+	if fallbackSaltDate == today && fallbackSalt != nil && time.Now().Before(fallbackSaltRetry) {
+		return fallbackSalt, nil
+	}
+
+	salt, err := loadVisitorSalt(today)
+	if err == nil {
+		visitorSaltDate, visitorSalt = today, salt
+		fallbackSaltDate, fallbackSalt = "", nil
+		return salt, nil
+	}
+
+	logging.Logger.Warn("获取访客标识 salt 失败，临时使用进程内 salt", zap.Error(err))
+	if fallbackSaltDate != today || fallbackSalt == nil {
+		salt, err := visitorid.NewSalt()
+		if err != nil {
+			return nil, err
+		}
+		fallbackSaltDate, fallbackSalt = today, salt
+	}
+	fallbackSaltRetry = time.Now().Add(visitorSaltRetryInterval)
+	return fallbackSalt, nil
+}
+
+// loadVisitorSalt 从 Redis 读取当天的 salt，不存在时生成并写入
+func loadVisitorSalt(date string) ([]byte, error) {
+	conn := repository.RedisPool.Get()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.Logger.Warn("Redis connection close failed", zap.Error(err))
+		}
+	}()
+
+	candidate, err := visitorid.NewSalt()
+	if err != nil {
+		return nil, err
+	}
+	key := constant.GetVisitorSaltKey(date)
+	if _, err := conn.Do("SET", key, candidate, "EX", visitorSaltTTL, "NX"); err != nil {
+		return nil, err
+	}
+	salt, err := redis.Bytes(conn.Do("GET", key))
+	if err != nil {
+		return nil, err
+	}
+	if len(salt) == 0 {
+		return nil, errEmptyVisitorSalt
+	}
+	return salt, nil
+}
@@ -0,0 +1,64 @@
+package view
+
+import (
+	"embed"
+	"html/template"
+	"io"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// RedirectPage HTML 跳转页面的渲染数据
+type RedirectPage struct {
+	Title     string        // 页面标题
+	TargetURL string        // 最终跳转地址
+	Delay     int           // meta refresh 延迟秒数
+	Head      template.HTML // 插入 <head> 的自定义片段（如统计代码），来自服务端配置
+}
+
+// RenderRedirect 按跳转方式渲染 HTML 跳转页面，mode 为 meta、js 或 frame
+func RenderRedirect(w io.Writer, mode string, page RedirectPage) error {
+	return templates.ExecuteTemplate(w, mode+".html", page)
+}
+
+// PreviewText 预览页面中已本地化的文案
+type PreviewText struct {
+	Title       string
+	Destination string
+	CreatedAt   string
+	Clicks      string
+	Notice      string
+	Continue    string
+}
+
+// PreviewPage 短链预览页面的渲染数据
+type PreviewPage struct {
+	Lang      string
+	ShortCode string
+	TargetURL string
+	CreatedAt string
+	Clicks    uint64
+	Text      PreviewText
+}
+
+// RenderPreview 渲染短链预览页面
+func RenderPreview(w io.Writer, page PreviewPage) error {
+	return templates.ExecuteTemplate(w, "preview.html", page)
+}
+
+// OpenGraphPage 社交平台抓取短链时返回的预览页面数据
+type OpenGraphPage struct {
+	URL         string // 短链地址，为空时不输出 og:url
+	TargetURL   string
+	Title       string
+	Description string
+	Image       string
+}
+
+// RenderOpenGraph 渲染包含 Open Graph 标签的预览页面
+func RenderOpenGraph(w io.Writer, page OpenGraphPage) error {
+	return templates.ExecuteTemplate(w, "og.html", page)
+}
@@ -0,0 +1,99 @@
+package botdetect
+
+import (
+	"bufio"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Reason 请求被判定为机器流量的原因，空字符串表示正常访问
+type Reason string
+
+const (
+	ReasonNone      Reason = ""
+	ReasonHead      Reason = "head"       // HEAD 请求（链接检查、网关扫描）
+	ReasonPrefetch  Reason = "prefetch"   // 浏览器预取/预渲染
+	ReasonMissingUA Reason = "missing_ua" // 缺少 User-Agent
+	ReasonUserAgent Reason = "user_agent" // User-Agent 命中机器人列表
+)
+
+// DefaultPatterns 内置的机器人 User-Agent 片段（不区分大小写）
+var DefaultPatterns = []string{
+	"bot", "crawler", "spider", "slurp", "scanner", "preview", "fetcher",
+	"curl", "wget", "python-requests", "python-urllib", "go-http-client", "okhttp", "java/", "libwww-perl",
+	"headlesschrome", "phantomjs", "lighthouse",
+	"barracuda", "mimecast", "proofpoint", "safelinks", "symantec", "trendmicro", "forcepoint", "sophos",
+	"urlscan", "virustotal", "microsoft office", "ms-office", "outlook-ios", "skypeuripreview",
+}
+
+// Classifier 根据请求方法、预取请求头和 User-Agent 判定机器流量
+type Classifier struct {
+	patterns []string
+}
+
+// New 使用给定的 User-Agent 片段创建分类器，片段统一转为小写后做子串匹配
+func New(patterns []string) *Classifier {
+	c := &Classifier{patterns: make([]string, 0, len(patterns))}
+	for _, pattern := range patterns {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			c.patterns = append(c.patterns, pattern)
+		}
+	}
+	return c
+}
+
+// LoadFile 从本地文件加载 User-Agent 片段，每行一个，空行和 # 开头的行被忽略
+func LoadFile(path string) (*Classifier, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	patterns := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return New(patterns), nil
+}
+
+// Classify 判定请求是否为机器流量，依次检查请求方法、预取请求头和 User-Agent
+func (c *Classifier) Classify(r *http.Request) Reason {
+	if r.Method == http.MethodHead {
+		return ReasonHead
+	}
+	if isPrefetch(r.Header) {
+		return ReasonPrefetch
+	}
+
+	userAgent := strings.ToLower(strings.TrimSpace(r.UserAgent()))
+	if userAgent == "" {
+		return ReasonMissingUA
+	}
+	for _, pattern := range c.patterns {
+		if strings.Contains(userAgent, pattern) {
+			return ReasonUserAgent
+		}
+	}
+	return ReasonNone
+}
+
+// isPrefetch 判断是否为浏览器的预取请求（Purpose/Sec-Purpose/X-Purpose/X-Moz）
+func isPrefetch(header http.Header) bool {
+	for _, name := range []string{"Purpose", "Sec-Purpose", "X-Purpose", "X-Moz"} {
+		value := strings.ToLower(header.Get(name))
+		if strings.Contains(value, "prefetch") || strings.Contains(value, "preview") || strings.Contains(value, "prerender") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package botdetect
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newRequest(method, userAgent string, headers map[string]string) *http.Request {
+	r, _ := http.NewRequest(method, "http://s.example.com/abc", nil)
+	if userAgent != "" {
+		r.Header.Set("User-Agent", userAgent)
+	}
+	for name, value := range headers {
+		r.Header.Set(name, value)
+	}
+	return r
+}
+
+func TestClassify(t *testing.T) {
+	const browser = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36"
+	c := New(DefaultPatterns)
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want Reason
+	}{
+		{"browser", newRequest(http.MethodGet, browser, nil), ReasonNone},
+		{"head request", newRequest(http.MethodHead, browser, nil), ReasonHead},
+		{"purpose prefetch", newRequest(http.MethodGet, browser, map[string]string{"Purpose": "prefetch"}), ReasonPrefetch},
+		{"sec-purpose prefetch", newRequest(http.MethodGet, browser, map[string]string{"Sec-Purpose": "prefetch;prerender"}), ReasonPrefetch},
+		{"firefox prefetch", newRequest(http.MethodGet, browser, map[string]string{"X-Moz": "prefetch"}), ReasonPrefetch},
+		{"missing user agent", newRequest(http.MethodGet, "", nil), ReasonMissingUA},
+		{"googlebot", newRequest(http.MethodGet, "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", nil), ReasonUserAgent},
+		{"curl", newRequest(http.MethodGet, "curl/8.5.0", nil), ReasonUserAgent},
+		{"case insensitive", newRequest(http.MethodGet, "Mozilla/5.0 BARRACUDA link protection", nil), ReasonUserAgent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Classify(tt.req); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bots.txt")
+	content := "# 邮件网关\nLinkScanner\n\n  acme-checker  \n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got := c.Classify(newRequest(http.MethodGet, "Mozilla/5.0 linkscanner/1.0", nil)); got != ReasonUserAgent {
+		t.Errorf("Classify(linkscanner) = %q, want %q", got, ReasonUserAgent)
+	}
+	if got := c.Classify(newRequest(http.MethodGet, "ACME-Checker", nil)); got != ReasonUserAgent {
+		t.Errorf("Classify(acme-checker) = %q, want %q", got, ReasonUserAgent)
+	}
+	// 文件中的列表替换内置列表
+	if got := c.Classify(newRequest(http.MethodGet, "curl/8.5.0", nil)); got != ReasonNone {
+		t.Errorf("Classify(curl) = %q, want %q", got, ReasonNone)
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadFile() with missing file should fail")
+	}
+}
@@ -0,0 +1,188 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// 支持的客户端 IP 请求头
+const (
+	HeaderForwarded     = "Forwarded" // RFC 7239
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIP       = "X-Real-IP"
+)
+
+// DefaultHeaders 未配置时读取的请求头
+// 只应配置代理一定会写入（覆盖或追加）的请求头，否则客户端可以伪造代理原样透传的其他请求头
+var DefaultHeaders = []string{HeaderXForwardedFor}
+
+// Resolver 根据受信任的代理列表解析真实客户端 IP
+// 只有直接连接方属于受信任代理时才读取转发请求头，并从右向左跳过受信任代理，取第一个不受信任的地址，
+// 因此客户端自行伪造的请求头（位于链的最左侧）不会被采用
+type Resolver struct {
+	trusted []*net.IPNet
+	headers []string
+}
+
+// New 创建解析器，trustedProxies 为 CIDR 或单个 IP，headers 为空时使用 DefaultHeaders
+func New(trustedProxies []string, headers []string) (*Resolver, error) {
+	r := &Resolver{headers: headers}
+	if len(r.headers) == 0 {
+		r.headers = DefaultHeaders
+	}
+	trusted, err := ParseCIDRs(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	r.trusted = trusted
+	return r, nil
+}
+
+// ParseCIDRs 解析 CIDR 列表（规则同 ParseCIDR），跳过空条目，任一条目无法解析时返回错误
+func ParseCIDRs(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		network, err := ParseCIDR(entry)
+		if err != nil {
+			return nil, err
+		}
+		if network != nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks, nil
+}
+
+// ContainsIP 判断 IP 是否属于任一网段
+func ContainsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCIDR 解析 CIDR，不带掩码的地址视为单个 IP，空字符串返回 nil
+func ParseCIDR(entry string) (*net.IPNet, error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return nil, nil
+	}
+	if !strings.Contains(entry, "/") {
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address: %s", entry)
+		}
+		if ip.To4() != nil {
+			return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, network, err := net.ParseCIDR(entry)
+	return network, err
+}
+
+// IsTrusted 判断地址是否属于受信任代理
+func (r *Resolver) IsTrusted(ip net.IP) bool {
+	return ContainsIP(r.trusted, ip)
+}
+
+// Resolve 返回请求的真实客户端 IP，无法解析时返回空字符串
+func (r *Resolver) Resolve(req *http.Request) string {
+	remote := parseHost(req.RemoteAddr)
+	if remote == nil {
+		return ""
+	}
+	if !r.IsTrusted(remote) {
+		return remote.String()
+	}
+
+	for _, header := range r.headers {
+		values := req.Header.Values(header)
+		if len(values) == 0 {
+			continue
+		}
+
+		var chain []string
+		switch http.CanonicalHeaderKey(header) {
+		case HeaderForwarded:
+			chain = parseForwarded(values)
+		case http.CanonicalHeaderKey(HeaderXRealIP):
+			// X-Real-IP 由最近一层代理写入，只取最后一个值
+			chain = []string{strings.TrimSpace(values[len(values)-1])}
+		default:
+			chain = splitList(values)
+		}
+		// 取第一个出现的请求头，解析失败时退回直接连接方的地址，不再尝试其他请求头
+		if ip := r.rightmostUntrusted(chain); ip != nil {
+			return ip.String()
+		}
+		return remote.String()
+	}
+	return remote.String()
+}
+
+// rightmostUntrusted 从右向左跳过受信任代理，返回第一个不受信任的地址
+// 遇到无法解析的地址（如 unknown 或混淆标识）时停止，不再信任其左侧的内容
+func (r *Resolver) rightmostUntrusted(chain []string) net.IP {
+	var last net.IP
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := parseHost(chain[i])
+		if ip == nil {
+			return nil
+		}
+		last = ip
+		if !r.IsTrusted(ip) {
+			return ip
+		}
+	}
+	// 整条链都是受信任代理时取最左侧的地址
+	return last
+}
+
+// splitList 拆分逗号分隔的多个请求头值
+func splitList(values []string) []string {
+	items := make([]string, 0)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// parseForwarded 按 RFC 7239 提取每个 forwarded-element 中的 for= 节点，缺少 for 的元素记为空
+func parseForwarded(values []string) []string {
+	nodes := make([]string, 0)
+	for _, element := range splitList(values) {
+		node := ""
+		for _, pair := range strings.Split(element, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(name), "for") {
+				node = strings.Trim(strings.TrimSpace(value), `"`)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// parseHost 解析 IP、IP:port、[IPv6] 或 [IPv6]:port 形式的地址
+func parseHost(addr string) net.IP {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return nil
+	}
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return net.ParseIP(host)
+	}
+	return net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]"))
+}
@@ -0,0 +1,214 @@
+package clientip
+
+import (
+	"net"
+	"net/http"
+	"testing"
+)
+
+func newRequest(remoteAddr string, headers map[string][]string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "http://s.example.com/abc", nil)
+	r.RemoteAddr = remoteAddr
+	for name, values := range headers {
+		for _, value := range values {
+			r.Header.Add(name, value)
+		}
+	}
+	return r
+}
+
+func TestResolve(t *testing.T) {
+	resolver, err := New([]string{"10.0.0.0/8", "192.168.1.10", "fd00::/8"}, []string{"Forwarded", "X-Forwarded-For", "X-Real-IP"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string][]string
+		want       string
+	}{
+		{
+			name:       "direct connection",
+			remoteAddr: "203.0.113.5:51234",
+			want:       "203.0.113.5",
+		},
+		{
+			name:       "spoofed X-Forwarded-For from untrusted peer is ignored",
+			remoteAddr: "203.0.113.5:51234",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4"}},
+			want:       "203.0.113.5",
+		},
+		{
+			name:       "spoofed X-Real-IP from untrusted peer is ignored",
+			remoteAddr: "203.0.113.5:51234",
+			headers:    map[string][]string{"X-Real-IP": {"1.2.3.4"}},
+			want:       "203.0.113.5",
+		},
+		{
+			name:       "spoofed Forwarded from untrusted peer is ignored",
+			remoteAddr: "203.0.113.5:51234",
+			headers:    map[string][]string{"Forwarded": {"for=1.2.3.4"}},
+			want:       "203.0.113.5",
+		},
+		{
+			name:       "X-Forwarded-For through trusted proxy",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.7"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "spoofed leftmost X-Forwarded-For entry is skipped",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.7"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "multiple trusted hops",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.7, 192.168.1.10", "10.1.1.1"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "all hops trusted",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"10.9.9.9, 10.1.1.1"}},
+			want:       "10.9.9.9",
+		},
+		{
+			name:       "X-Real-IP through trusted proxy",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Real-IP": {"198.51.100.7"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "Forwarded with quoted IPv6 and port",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"Forwarded": {`for="[2001:db8::1]:4711";proto=https, for=10.1.1.1`}},
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "spoofed Forwarded element is skipped",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"Forwarded": {"for=1.2.3.4, for=198.51.100.7;by=10.0.0.2"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "Forwarded takes precedence over X-Forwarded-For",
+			remoteAddr: "10.0.0.2:443",
+			headers: map[string][]string{
+				"Forwarded":       {"for=198.51.100.7"},
+				"X-Forwarded-For": {"198.51.100.8"},
+			},
+			want: "198.51.100.7",
+		},
+		{
+			name:       "obfuscated Forwarded node falls back to the peer",
+			remoteAddr: "10.0.0.2:443",
+			headers: map[string][]string{
+				"Forwarded":       {"for=_hidden"},
+				"X-Forwarded-For": {"198.51.100.8"},
+			},
+			want: "10.0.0.2",
+		},
+		{
+			name:       "unparseable hop stops the walk",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.7, unknown"}},
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "trusted IPv6 proxy",
+			remoteAddr: "[fd00::1]:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"2001:db8::2"}},
+			want:       "2001:db8::2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.Resolve(newRequest(tt.remoteAddr, tt.headers)); got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveWithoutTrustedProxies(t *testing.T) {
+	resolver, err := New(nil, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r := newRequest("10.0.0.2:443", map[string][]string{
+		"Forwarded":       {"for=1.2.3.4"},
+		"X-Forwarded-For": {"1.2.3.4"},
+		"X-Real-IP":       {"1.2.3.4"},
+	})
+	if got := resolver.Resolve(r); got != "10.0.0.2" {
+		t.Errorf("Resolve() = %q, want %q", got, "10.0.0.2")
+	}
+}
+
+func TestResolveConfiguredHeaders(t *testing.T) {
+	// 只配置 X-Real-IP 时忽略 X-Forwarded-For
+	resolver, err := New([]string{"10.0.0.0/8"}, []string{"X-Real-IP"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r := newRequest("10.0.0.2:443", map[string][]string{"X-Forwarded-For": {"1.2.3.4"}})
+	if got := resolver.Resolve(r); got != "10.0.0.2" {
+		t.Errorf("Resolve() = %q, want %q", got, "10.0.0.2")
+	}
+
+	// 默认只读取 X-Forwarded-For，代理透传的 Forwarded/X-Real-IP 不会被采用
+	resolver, err = New([]string{"10.0.0.0/8"}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r = newRequest("10.0.0.2:443", map[string][]string{
+		"Forwarded":       {"for=1.2.3.4"},
+		"X-Real-IP":       {"1.2.3.4"},
+		"X-Forwarded-For": {"198.51.100.7"},
+	})
+	if got := resolver.Resolve(r); got != "198.51.100.7" {
+		t.Errorf("Resolve() = %q, want %q", got, "198.51.100.7")
+	}
+}
+
+func TestNewInvalidCIDR(t *testing.T) {
+	if _, err := New([]string{"10.0.0.0/33"}, nil); err == nil {
+		t.Error("New() with invalid CIDR should fail")
+	}
+	if _, err := New([]string{"not-an-ip"}, nil); err == nil {
+		t.Error("New() with invalid IP should fail")
+	}
+}
+
+func TestParseCIDRs(t *testing.T) {
+	networks, err := ParseCIDRs([]string{"10.0.0.0/8", " 192.168.1.5 ", "", "2001:db8::1"})
+	if err != nil {
+		t.Fatalf("ParseCIDRs() error = %v", err)
+	}
+	if len(networks) != 3 {
+		t.Fatalf("ParseCIDRs() returned %d networks, want 3", len(networks))
+	}
+
+	for ip, want := range map[string]bool{
+		"10.1.2.3":    true,
+		"192.168.1.5": true,
+		"192.168.1.6": false,
+		"2001:db8::1": true,
+		"2001:db8::2": false,
+	} {
+		if got := ContainsIP(networks, net.ParseIP(ip)); got != want {
+			t.Errorf("ContainsIP(%s) = %v, want %v", ip, got, want)
+		}
+	}
+
+	for _, entries := range [][]string{{"10.0.0.0/33"}, {"10.0.0.0/8", "office"}} {
+		if _, err := ParseCIDRs(entries); err == nil {
+			t.Errorf("ParseCIDRs(%q) should fail", entries)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// v1Prefix 文本格式（v1）的头部前缀，v2Signature 二进制格式（v2）的 12 字节签名
+var (
+	v1Prefix    = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// v1MaxLength v1 头部（含 CRLF）的最大长度
+const v1MaxLength = 107
+
+// ErrInvalidHeader PROXY protocol 头部格式错误
+var ErrInvalidHeader = errors.New("invalid PROXY protocol header")
+
+// Listener 解析 PROXY protocol（v1/v2）头部的监听器
+// 只有来自受信任地址的连接才会解析头部，其他连接原样透传，避免客户端伪造来源地址
+type Listener struct {
+	net.Listener
+	Trusted       func(ip net.IP) bool // 为 nil 时信任所有连接
+	HeaderTimeout time.Duration        // 读取头部的超时时间，0 表示不限制
+}
+
+// NewListener 包装已有的监听器
+func NewListener(inner net.Listener, trusted func(ip net.IP) bool, headerTimeout time.Duration) *Listener {
+	return &Listener{Listener: inner, Trusted: trusted, HeaderTimeout: headerTimeout}
+}
+
+// Accept 接受连接，头部延迟到第一次读取或获取 RemoteAddr 时解析，不阻塞 Accept 循环
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	trusted := true
+	if l.Trusted != nil {
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+			trusted = l.Trusted(addr.IP)
+		} else {
+			trusted = false
+		}
+	}
+	if !trusted {
+		return conn, nil
+	}
+	return &Conn{Conn: conn, reader: bufio.NewReader(conn), headerTimeout: l.HeaderTimeout}, nil
+}
+
+// Conn 解析过 PROXY protocol 头部的连接，RemoteAddr/LocalAddr 返回头部中记录的原始地址
+type Conn struct {
+	net.Conn
+	reader        *bufio.Reader
+	headerTimeout time.Duration
+
+	once       sync.Once
+	err        error
+	remoteAddr net.Addr
+	localAddr  net.Addr
+}
+
+// Read 读取头部之后的数据
+func (c *Conn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(b)
+}
+
+// RemoteAddr 返回头部中的客户端地址，没有头部或为 LOCAL/UNKNOWN 时返回实际连接地址
+func (c *Conn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr 返回头部中的目标地址
+func (c *Conn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.localAddr != nil {
+		return c.localAddr
+	}
+	return c.Conn.LocalAddr()
+}
+
+// readHeader 读取并解析头部；连接不以 PROXY protocol 签名开头时视为没有头部（例如负载均衡器的健康检查）
+func (c *Conn) readHeader() {
+	if c.headerTimeout > 0 {
+		_ = c.Conn.SetReadDeadline(time.Now().Add(c.headerTimeout))
+		defer func() { _ = c.Conn.SetReadDeadline(time.Time{}) }()
+	}
+
+	first, err := c.reader.Peek(1)
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			c.err = err
+		}
+		return
+	}
+
+	switch first[0] {
+	case v1Prefix[0]:
+		if prefix, err := c.reader.Peek(len(v1Prefix)); err == nil && bytes.Equal(prefix, v1Prefix) {
+			c.err = c.readV1()
+		}
+	case v2Signature[0]:
+		if signature, err := c.reader.Peek(len(v2Signature)); err == nil && bytes.Equal(signature, v2Signature) {
+			c.err = c.readV2()
+		}
+	}
+	if c.err != nil {
+		_ = c.Conn.Close()
+	}
+}
+
+// readV1 解析文本格式：PROXY TCP4|TCP6|UNKNOWN src dst sport dport\r\n
+func (c *Conn) readV1() error {
+	line := make([]byte, 0, v1MaxLength)
+	for {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= v1MaxLength {
+			return ErrInvalidHeader
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return ErrInvalidHeader
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return ErrInvalidHeader
+	}
+
+	src, err := parseV1Addr(fields[2], fields[4], fields[1] == "TCP4")
+	if err != nil {
+		return err
+	}
+	dst, err := parseV1Addr(fields[3], fields[5], fields[1] == "TCP4")
+	if err != nil {
+		return err
+	}
+	c.remoteAddr, c.localAddr = src, dst
+	return nil
+}
+
+func parseV1Addr(host, port string, v4 bool) (*net.TCPAddr, error) {
+	ip := net.ParseIP(host)
+	if ip == nil || (ip.To4() != nil) != v4 {
+		return nil, ErrInvalidHeader
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, ErrInvalidHeader
+	}
+	return &net.TCPAddr{IP: ip, Port: int(p)}, nil
+}
+
+// readV2 解析二进制格式：签名(12) + 版本/命令(1) + 地址族/协议(1) + 长度(2) + 地址
+func (c *Conn) readV2() error {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return err
+	}
+	if header[12]>>4 != 2 {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidHeader, header[12]>>4)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return err
+	}
+
+	switch header[12] & 0x0F {
+	case 0x0: // LOCAL：代理自身发起的连接（如健康检查），使用实际连接地址
+		return nil
+	case 0x1: // PROXY
+	default:
+		return fmt.Errorf("%w: unsupported command", ErrInvalidHeader)
+	}
+
+	// 只处理基于流的 TCP over IPv4/IPv6，其余地址族忽略地址信息
+	switch header[13] {
+	case 0x11: // TCP over IPv4
+		if len(payload) < 12 {
+			return ErrInvalidHeader
+		}
+		c.remoteAddr = &net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:10]))}
+		c.localAddr = &net.TCPAddr{IP: net.IP(payload[4:8]), Port: int(binary.BigEndian.Uint16(payload[10:12]))}
+	case 0x21: // TCP over IPv6
+		if len(payload) < 36 {
+			return ErrInvalidHeader
+		}
+		c.remoteAddr = &net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:34]))}
+		c.localAddr = &net.TCPAddr{IP: net.IP(payload[16:32]), Port: int(binary.BigEndian.Uint16(payload[34:36]))}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package proxyproto
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// serve 通过 Listener 接受一个连接，写入 payload 后返回服务端看到的连接与读到的数据
+func serve(t *testing.T, trusted func(net.IP) bool, payload []byte) (net.Addr, string, error) {
+	t.Helper()
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln := NewListener(inner, trusted, time.Second)
+	defer ln.Close()
+
+	go func() {
+		client, err := net.Dial("tcp", inner.Addr().String())
+		if err != nil {
+			return
+		}
+		defer client.Close()
+		_, _ = client.Write(payload)
+	}()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	addr := conn.RemoteAddr()
+	data, err := io.ReadAll(conn)
+	return addr, string(data), err
+}
+
+func trustAll(net.IP) bool  { return true }
+func trustNone(net.IP) bool { return false }
+
+func TestV1(t *testing.T) {
+	addr, data, err := serve(t, trustAll, []byte("PROXY TCP4 198.51.100.7 203.0.113.1 51234 443\r\nGET / HTTP/1.1\r\n"))
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	if addr.String() != "198.51.100.7:51234" {
+		t.Errorf("RemoteAddr() = %s, want 198.51.100.7:51234", addr)
+	}
+	if data != "GET / HTTP/1.1\r\n" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestV1IPv6(t *testing.T) {
+	addr, _, err := serve(t, trustAll, []byte("PROXY TCP6 2001:db8::1 2001:db8::2 51234 443\r\n"))
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	if addr.String() != "[2001:db8::1]:51234" {
+		t.Errorf("RemoteAddr() = %s, want [2001:db8::1]:51234", addr)
+	}
+}
+
+func TestV1Unknown(t *testing.T) {
+	addr, data, err := serve(t, trustAll, []byte("PROXY UNKNOWN\r\nping"))
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	if host, _, _ := net.SplitHostPort(addr.String()); host != "127.0.0.1" {
+		t.Errorf("RemoteAddr() = %s, want the real peer", addr)
+	}
+	if data != "ping" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestV2(t *testing.T) {
+	header := append([]byte{}, v2Signature...)
+	header = append(header, 0x21, 0x11, 0, 12)
+	header = append(header, 198, 51, 100, 7, 203, 0, 113, 1)
+	header = binary.BigEndian.AppendUint16(header, 51234)
+	header = binary.BigEndian.AppendUint16(header, 443)
+
+	addr, data, err := serve(t, trustAll, append(header, []byte("hello")...))
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	if addr.String() != "198.51.100.7:51234" {
+		t.Errorf("RemoteAddr() = %s, want 198.51.100.7:51234", addr)
+	}
+	if data != "hello" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestV2Local(t *testing.T) {
+	header := append([]byte{}, v2Signature...)
+	header = append(header, 0x20, 0x00, 0, 0)
+
+	addr, data, err := serve(t, trustAll, append(header, []byte("hello")...))
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	if host, _, _ := net.SplitHostPort(addr.String()); host != "127.0.0.1" {
+		t.Errorf("RemoteAddr() = %s, want the real peer", addr)
+	}
+	if data != "hello" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestUntrustedPeerHeaderIsNotParsed(t *testing.T) {
+	payload := "PROXY TCP4 198.51.100.7 203.0.113.1 51234 443\r\n"
+	addr, data, err := serve(t, trustNone, []byte(payload))
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	if host, _, _ := net.SplitHostPort(addr.String()); host != "127.0.0.1" {
+		t.Errorf("RemoteAddr() = %s, want the real peer", addr)
+	}
+	if data != payload {
+		t.Errorf("data = %q, want the header passed through", data)
+	}
+}
+
+func TestWithoutHeader(t *testing.T) {
+	addr, data, err := serve(t, trustAll, []byte("GET /healthz HTTP/1.1\r\n"))
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	if host, _, _ := net.SplitHostPort(addr.String()); host != "127.0.0.1" {
+		t.Errorf("RemoteAddr() = %s, want the real peer", addr)
+	}
+	if data != "GET /healthz HTTP/1.1\r\n" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestInvalidHeader(t *testing.T) {
+	for _, payload := range []string{
+		"PROXY TCP4 not-an-ip 203.0.113.1 51234 443\r\n",
+		"PROXY TCP4 198.51.100.7 203.0.113.1 99999 443\r\n",
+		"PROXY TCP4 2001:db8::1 203.0.113.1 51234 443\r\n",
+		"PROXY TCP4 198.51.100.7 203.0.113.1 51234\r\n",
+		"PROXY TCP4 198.51.100.7 203.0.113.1 51234 443\n",
+	} {
+		if _, _, err := serve(t, trustAll, []byte(payload)); err == nil {
+			t.Errorf("payload %q should fail", payload)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryCleanupInterval 清理过期 key 的最小间隔
+const memoryCleanupInterval = time.Minute
+
+// Memory 进程内的滑动窗口限流器（记录窗口内每次请求的时间），多实例部署时各实例单独计数
+type Memory struct {
+	mu          sync.Mutex
+	entries     map[string]*memoryEntry
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+// memoryEntry 单个 key 窗口内的请求时间（升序）及其窗口长度，不同规则的 key 窗口可能不同
+type memoryEntry struct {
+	hits   []time.Time
+	window time.Duration
+}
+
+// NewMemory 创建进程内限流器
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]*memoryEntry), now: time.Now}
+}
+
+// Allow 实现 Limiter
+func (m *Memory) Allow(_ context.Context, key string, limit int, window time.Duration) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.cleanup(now)
+
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoryEntry{}
+		m.entries[key] = entry
+	}
+	entry.window = window
+
+	hits := prune(entry.hits, now.Add(-window))
+	if len(hits) >= limit {
+		entry.hits = hits
+		return Result{Allowed: false, RetryAfter: hits[0].Add(window).Sub(now)}, nil
+	}
+
+	entry.hits = append(hits, now)
+	return Result{Allowed: true, Remaining: limit - len(hits) - 1}, nil
+}
+
+// cleanup 定期删除窗口内已没有请求的 key（按各 key 自身的窗口判断），避免被大量不同 key 撑大内存
+func (m *Memory) cleanup(now time.Time) {
+	if now.Sub(m.lastCleanup) < memoryCleanupInterval {
+		return
+	}
+	m.lastCleanup = now
+	for key, entry := range m.entries {
+		if len(entry.hits) == 0 || !entry.hits[len(entry.hits)-1].After(now.Add(-entry.window)) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// prune 去掉 since 之前（含）的请求记录，hits 按时间升序
+func prune(hits []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(since) {
+		i++
+	}
+	return hits[i:]
+}
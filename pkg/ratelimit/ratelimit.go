@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result 一次限流判定的结果
+type Result struct {
+	Allowed    bool
+	Remaining  int           // 当前窗口内剩余的请求数
+	RetryAfter time.Duration // 被拒绝时距离可以再次请求的时间
+}
+
+// Limiter 滑动窗口限流器：key 在任意 window 时长内最多允许 limit 次请求
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error)
+}
+
+// Fallback 优先使用 primary，primary 出错（例如 Redis 不可用）时改用 fallback，onError 用于记录日志
+type Fallback struct {
+	Primary Limiter
+	Backup  Limiter
+	OnError func(err error)
+}
+
+// Allow 实现 Limiter
+func (f *Fallback) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	result, err := f.Primary.Allow(ctx, key, limit, window)
+	if err == nil {
+		return result, nil
+	}
+	if f.OnError != nil {
+		f.OnError(err)
+	}
+	return f.Backup.Allow(ctx, key, limit, window)
+}
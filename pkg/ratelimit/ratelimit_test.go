@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemorySlidingWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		result, _ := m.Allow(ctx, "ip:1", 3, time.Minute)
+		if !result.Allowed {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+		if result.Remaining != 2-i {
+			t.Errorf("request %d remaining = %d, want %d", i+1, result.Remaining, 2-i)
+		}
+		now = now.Add(10 * time.Second)
+	}
+
+	// 第 4 次在窗口内被拒绝，需等待最早一次请求滑出窗口
+	result, _ := m.Allow(ctx, "ip:1", 3, time.Minute)
+	if result.Allowed {
+		t.Fatal("request 4 should be rejected")
+	}
+	if result.RetryAfter != 30*time.Second {
+		t.Errorf("RetryAfter = %s, want 30s", result.RetryAfter)
+	}
+
+	// 其他 key 不受影响
+	if result, _ := m.Allow(ctx, "ip:2", 3, time.Minute); !result.Allowed {
+		t.Error("another key should be allowed")
+	}
+
+	// 最早一次请求滑出窗口后再次放行
+	now = now.Add(30 * time.Second)
+	if result, _ := m.Allow(ctx, "ip:1", 3, time.Minute); !result.Allowed {
+		t.Error("request after the window slides should be allowed")
+	}
+}
+
+func TestMemoryCleanup(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, _ = m.Allow(ctx, "old", 1, time.Second)
+	now = now.Add(2 * memoryCleanupInterval)
+	_, _ = m.Allow(ctx, "new", 1, time.Second)
+
+	if _, ok := m.entries["old"]; ok {
+		t.Error("expired key should be removed")
+	}
+}
+
+func TestMemoryCleanupUsesKeyWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 长窗口的 key 在短窗口请求触发清理时不能被提前删除，否则限流会被重置
+	for i := 0; i < 2; i++ {
+		_, _ = m.Allow(ctx, "long", 2, time.Hour)
+	}
+	now = now.Add(2 * memoryCleanupInterval)
+	_, _ = m.Allow(ctx, "short", 1, time.Second)
+
+	if _, ok := m.entries["long"]; !ok {
+		t.Fatal("key still inside its own window should be kept")
+	}
+	if result, _ := m.Allow(ctx, "long", 2, time.Hour); result.Allowed {
+		t.Error("long window limit should still apply after cleanup")
+	}
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, int, time.Duration) (Result, error) {
+	return Result{}, errors.New("redis unavailable")
+}
+
+func TestFallback(t *testing.T) {
+	var reported error
+	limiter := &Fallback{
+		Primary: failingLimiter{},
+		Backup:  NewMemory(),
+		OnError: func(err error) { reported = err },
+	}
+	ctx := context.Background()
+
+	result, err := limiter.Allow(ctx, "ip:1", 1, time.Minute)
+	if err != nil || !result.Allowed {
+		t.Fatalf("first request = %+v, %v; want allowed", result, err)
+	}
+	if reported == nil {
+		t.Error("primary error should be reported")
+	}
+	if result, _ := limiter.Allow(ctx, "ip:1", 1, time.Minute); result.Allowed {
+		t.Error("fallback limiter should enforce the limit")
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// slidingWindowScript 基于有序集合的滑动窗口：先删除窗口外的记录，未超限时写入本次请求
+// KEYS[1]：限流 key；ARGV：当前时间（毫秒）、窗口（毫秒）、上限、本次请求的唯一成员
+// 返回 {是否允许, 剩余次数, 需要等待的毫秒数}
+var slidingWindowScript = redis.NewScript(1, `
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[4])
+  redis.call('PEXPIRE', KEYS[1], window)
+  return {1, limit - count - 1, 0}
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local wait = window
+if oldest[2] then
+  wait = tonumber(oldest[2]) + window - now
+end
+return {0, 0, wait}
+`)
+
+// Redis 基于 Redis 有序集合的滑动窗口限流器，多实例共享计数
+type Redis struct {
+	Pool   *redis.Pool
+	Prefix string // key 前缀
+}
+
+// Allow 实现 Limiter
+func (r *Redis) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	conn, err := r.Pool.GetContext(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer conn.Close()
+
+	member := make([]byte, 8)
+	if _, err := rand.Read(member); err != nil {
+		return Result{}, err
+	}
+
+	now := time.Now().UnixMilli()
+	values, err := redis.Int64s(slidingWindowScript.Do(conn, r.Prefix+key, now, window.Milliseconds(), limit,
+		hex.EncodeToString(member)))
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 3 {
+		return Result{}, redis.Error("unexpected rate limit script reply")
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
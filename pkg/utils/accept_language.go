@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PrimaryLanguage 返回 Accept-Language 中权重最高的语言标签（如 zh-CN），没有时返回空字符串
+func PrimaryLanguage(acceptLanguage string) string {
+	best, bestQ := "", -1.0
+	for _, item := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// CanonicalizeURL 规范化目标地址，用于判断两个地址是否指向同一目标：
+// scheme 与 host 转小写，去掉默认端口，空路径补为 /，移除跟踪参数后按参数名排序查询串。
+// trackingParams 中以 * 结尾的项按前缀匹配（如 utm_*），其余按参数名精确匹配（不区分大小写）
+func CanonicalizeURL(rawURL string, trackingParams []string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]" // IPv6
+	} else {
+		u.Host = host
+	}
+
+	if u.Path == "" && u.Host != "" {
+		u.Path = "/"
+	}
+
+	query := u.Query()
+	for key := range query {
+		if isTrackingParam(key, trackingParams) {
+			delete(query, key)
+		}
+	}
+	// Encode 会按参数名排序
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+func isTrackingParam(key string, trackingParams []string) bool {
+	key = strings.ToLower(key)
+	for _, param := range trackingParams {
+		param = strings.ToLower(param)
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == param {
+			return true
+		}
+	}
+	return false
+}
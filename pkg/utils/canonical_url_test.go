@@ -0,0 +1,48 @@
+package utils
+
+import "testing"
+
+func TestCanonicalizeURL(t *testing.T) {
+	tracking := []string{"utm_*", "fbclid", "GCLID"}
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"lower case scheme and host", "HTTPS://Example.COM/Path", "https://example.com/Path"},
+		{"default https port removed", "https://example.com:443/a", "https://example.com/a"},
+		{"default http port removed", "http://example.com:80/a", "http://example.com/a"},
+		{"non default port kept", "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"http port on https kept", "https://example.com:80/a", "https://example.com:80/a"},
+		{"empty path becomes slash", "https://example.com", "https://example.com/"},
+		{"ipv6 host", "https://[2001:DB8::1]/a", "https://[2001:db8::1]/a"},
+		{"ipv6 host with default port", "https://[2001:db8::1]:443/a", "https://[2001:db8::1]/a"},
+		{"ipv6 host with port", "http://[2001:db8::1]:8080/a", "http://[2001:db8::1]:8080/a"},
+		{"query sorted by name", "https://example.com/?b=2&a=1&c=3", "https://example.com/?a=1&b=2&c=3"},
+		{"tracking prefix wildcard", "https://example.com/?utm_source=x&utm_medium=y&id=1", "https://example.com/?id=1"},
+		{"tracking wildcard is case insensitive", "https://example.com/?UTM_Source=x&id=1", "https://example.com/?id=1"},
+		{"tracking exact match", "https://example.com/?fbclid=abc&gclid=def&id=1", "https://example.com/?id=1"},
+		{"exact match is not a prefix", "https://example.com/?fbclid_extra=1", "https://example.com/?fbclid_extra=1"},
+		{"only tracking params", "https://example.com/a?utm_source=x", "https://example.com/a"},
+		{"empty query dropped", "https://example.com/a?", "https://example.com/a"},
+		{"fragment kept", "https://example.com/a?b=1#top", "https://example.com/a?b=1#top"},
+		{"surrounding whitespace trimmed", "  https://example.com/a  ", "https://example.com/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalizeURL(tt.url, tracking)
+			if err != nil {
+				t.Fatalf("CanonicalizeURL(%q) error = %v", tt.url, err)
+			}
+			if got != tt.want {
+				t.Errorf("CanonicalizeURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CanonicalizeURL("https://exa mple.com/%zz", tracking); err == nil {
+		t.Error("CanonicalizeURL() with invalid URL should fail")
+	}
+}
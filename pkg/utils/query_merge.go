@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MergeQueryParams 将参数追加到地址的查询串末尾；地址中已存在的同名参数保持不变。
+// 直接操作字符串，原有的路径、查询串与片段（包括模板占位符）不做重新编码
+func MergeQueryParams(rawURL string, params [][2]string) string {
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+	_, rawQuery, hasQuery := strings.Cut(base, "?")
+
+	existing, _ := url.ParseQuery(rawQuery)
+	var appended []string
+	for _, p := range params {
+		if existing.Has(p[0]) {
+			continue
+		}
+		appended = append(appended, url.QueryEscape(p[0])+"="+url.QueryEscape(p[1]))
+	}
+	if len(appended) == 0 {
+		return rawURL
+	}
+
+	switch {
+	case !hasQuery:
+		base += "?"
+	case rawQuery != "" && !strings.HasSuffix(rawQuery, "&"):
+		base += "&"
+	}
+	merged := base + strings.Join(appended, "&")
+	if hasFragment {
+		merged += "#" + fragment
+	}
+	return merged
+}
@@ -0,0 +1,34 @@
+package utils
+
+import "testing"
+
+func TestMergeQueryParams(t *testing.T) {
+	utm := [][2]string{{"utm_source", "news letter"}, {"utm_medium", "email"}}
+
+	tests := []struct {
+		name   string
+		url    string
+		params [][2]string
+		want   string
+	}{
+		{"no query", "https://example.com/a", utm, "https://example.com/a?utm_source=news+letter&utm_medium=email"},
+		{"existing query", "https://example.com/a?x=1", utm, "https://example.com/a?x=1&utm_source=news+letter&utm_medium=email"},
+		{"empty query", "https://example.com/a?", utm, "https://example.com/a?utm_source=news+letter&utm_medium=email"},
+		{"trailing ampersand", "https://example.com/a?x=1&", utm, "https://example.com/a?x=1&utm_source=news+letter&utm_medium=email"},
+		{"fragment kept at end", "https://example.com/a#top", utm, "https://example.com/a?utm_source=news+letter&utm_medium=email#top"},
+		{"query and fragment", "https://example.com/a?x=1#s?y=2", utm, "https://example.com/a?x=1&utm_source=news+letter&utm_medium=email#s?y=2"},
+		{"existing key wins", "https://example.com/a?utm_source=ads", utm, "https://example.com/a?utm_source=ads&utm_medium=email"},
+		{"all keys exist", "https://example.com/a?utm_source=ads&utm_medium=cpc", utm, "https://example.com/a?utm_source=ads&utm_medium=cpc"},
+		{"no params", "https://example.com/a?x=1", nil, "https://example.com/a?x=1"},
+		{"template not re-encoded", "https://example.com/p/{code}?src={ref}", utm, "https://example.com/p/{code}?src={ref}&utm_source=news+letter&utm_medium=email"},
+		{"special characters escaped", "https://example.com/", [][2]string{{"q", "a&b=c#d"}}, "https://example.com/?q=a%26b%3Dc%23d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeQueryParams(tt.url, tt.params); got != tt.want {
+				t.Errorf("MergeQueryParams(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
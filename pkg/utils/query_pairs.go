@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ParseQueryPairs 按出现顺序解析查询串，无法解码的参数会被忽略
+func ParseQueryPairs(rawQuery string) [][2]string {
+	pairs := make([][2]string, 0)
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		key, err := url.QueryUnescape(key)
+		if err != nil || key == "" {
+			continue
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			continue
+		}
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return pairs
+}
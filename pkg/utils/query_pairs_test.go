@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQueryPairs(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  [][2]string
+	}{
+		{"empty", "", [][2]string{}},
+		{"keeps order", "b=2&a=1&c=3", [][2]string{{"b", "2"}, {"a", "1"}, {"c", "3"}}},
+		{"repeated keys", "a=1&a=2", [][2]string{{"a", "1"}, {"a", "2"}}},
+		{"key without value", "flag&a=1", [][2]string{{"flag", ""}, {"a", "1"}}},
+		{"empty value", "a=", [][2]string{{"a", ""}}},
+		{"decodes values", "q=hello+world&r=%2Fpath%3F", [][2]string{{"q", "hello world"}, {"r", "/path?"}}},
+		{"value containing equals", "a=b=c", [][2]string{{"a", "b=c"}}},
+		{"skips empty parts", "&&a=1&", [][2]string{{"a", "1"}}},
+		{"skips empty key", "=1&a=2", [][2]string{{"a", "2"}}},
+		{"skips undecodable key", "%zz=1&a=2", [][2]string{{"a", "2"}}},
+		{"skips undecodable value", "a=%zz&b=2", [][2]string{{"b", "2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseQueryPairs(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQueryPairs(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
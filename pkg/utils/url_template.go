@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// 目标地址模板：形如 https://shop.example.com/track/{code}?src={ref}&lang={lang}
+// 占位符只能出现在路径、查询串与片段中，不能出现在 scheme、用户信息和主机部分；
+// 替换值按所在位置做 URL 转义（路径中使用 PathEscape，查询串与片段中使用 QueryEscape），
+// 因此替换值无法引入新的路径层级、查询参数或片段，也不会被再次当作模板解析；
+// 路径中值为 "." 或 ".." 时替换为空，避免形成相对路径段。
+
+// maxTemplateValueLength 单个替换值的最大字符数，超出部分截断
+const maxTemplateValueLength = 256
+
+// urlTemplatePart 模板片段：字面量或占位符
+type urlTemplatePart struct {
+	literal string
+	name    string // 不为空时表示占位符
+	inPath  bool   // 占位符是否位于路径中
+}
+
+// HasURLPlaceholders 判断地址中是否包含 {name} 形式的模板占位符，名称不合法的花括号按普通字符处理
+func HasURLPlaceholders(rawURL string) bool {
+	for rest := rawURL; ; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			return false
+		}
+		rest = rest[start+1:]
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return false
+		}
+		if isTemplateName(rest[:end]) {
+			return true
+		}
+	}
+}
+
+// ValidateURLTemplate 校验模板语法与占位符位置，并确认填充后是合法的 URL
+func ValidateURLTemplate(rawURL string) error {
+	parts, err := parseURLTemplate(rawURL)
+	if err != nil {
+		return err
+	}
+
+	rendered := renderURLTemplate(parts, func(string) string { return "x" })
+	u, err := url.ParseRequestURI(rendered)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("error.target_url_template_invalid")
+	}
+	return nil
+}
+
+// RenderURLTemplate 用 lookup 返回的值填充占位符，模板不合法时原样返回
+func RenderURLTemplate(rawURL string, lookup func(name string) string) string {
+	if !HasURLPlaceholders(rawURL) {
+		return rawURL
+	}
+	parts, err := parseURLTemplate(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return renderURLTemplate(parts, lookup)
+}
+
+func renderURLTemplate(parts []urlTemplatePart, lookup func(name string) string) string {
+	var b strings.Builder
+	for _, part := range parts {
+		if part.name == "" {
+			b.WriteString(part.literal)
+			continue
+		}
+
+		value := truncateRunes(lookup(part.name), maxTemplateValueLength)
+		if part.inPath {
+			// PathEscape 不转义 "."，而浏览器会把 "%2E" 同样当作点号段处理，
+			// 值恰好为 "." 或 ".." 时替换为空，避免跳到模板之外的路径
+			if isDotSegment(value) {
+				continue
+			}
+			b.WriteString(url.PathEscape(value))
+		} else {
+			b.WriteString(url.QueryEscape(value))
+		}
+	}
+	return b.String()
+}
+
+// isDotSegment 判断值是否为 URL 路径中的相对路径段
+func isDotSegment(value string) bool {
+	return value == "." || value == ".."
+}
+
+// parseURLTemplate 拆分模板，占位符名称只能包含字母、数字和下划线，且不能以数字开头
+func parseURLTemplate(rawURL string) ([]urlTemplatePart, error) {
+	authorityEnd := templateAuthorityEnd(rawURL)
+	if authorityEnd < 0 {
+		return nil, fmt.Errorf("error.target_url_template_invalid")
+	}
+
+	parts := make([]urlTemplatePart, 0)
+	inPath := true
+	literalStart := 0
+	for i := 0; i < len(rawURL); i++ {
+		switch rawURL[i] {
+		case '?', '#':
+			if i >= authorityEnd {
+				inPath = false
+			}
+		case '}':
+			return nil, fmt.Errorf("error.target_url_template_invalid")
+		case '{':
+			if i < authorityEnd {
+				// scheme、用户信息与主机中不允许出现占位符
+				return nil, fmt.Errorf("error.target_url_placeholder_position")
+			}
+			end := strings.IndexByte(rawURL[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("error.target_url_template_invalid")
+			}
+			name := rawURL[i+1 : i+end]
+			if !isTemplateName(name) {
+				return nil, fmt.Errorf("error.target_url_template_invalid")
+			}
+
+			if i > literalStart {
+				parts = append(parts, urlTemplatePart{literal: rawURL[literalStart:i]})
+			}
+			parts = append(parts, urlTemplatePart{name: name, inPath: inPath})
+			i += end
+			literalStart = i + 1
+		}
+	}
+	if literalStart < len(rawURL) {
+		parts = append(parts, urlTemplatePart{literal: rawURL[literalStart:]})
+	}
+	return parts, nil
+}
+
+// templateAuthorityEnd 返回 scheme://authority 部分结束的位置（即路径、查询串或片段的起点）
+func templateAuthorityEnd(rawURL string) int {
+	schemeEnd := strings.Index(rawURL, "://")
+	if schemeEnd <= 0 {
+		return -1
+	}
+	start := schemeEnd + len("://")
+	if end := strings.IndexAny(rawURL[start:], "/?#"); end >= 0 {
+		return start + end
+	}
+	return len(rawURL)
+}
+
+func isTemplateName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	return string([]rune(value)[:limit])
+}
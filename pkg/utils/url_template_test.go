@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateURLTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{"path query and fragment", "https://shop.example.com/p/{code}?src={ref}&lang={lang}#{country}", ""},
+		{"query right after host", "https://shop.example.com?src={ref}", ""},
+		{"placeholder in host", "https://{host}.example.com/", "error.target_url_placeholder_position"},
+		{"placeholder as whole host", "https://{host}/", "error.target_url_placeholder_position"},
+		{"placeholder in scheme", "{scheme}://example.com/", "error.target_url_placeholder_position"},
+		{"placeholder in userinfo", "https://{user}@example.com/", "error.target_url_placeholder_position"},
+		{"placeholder in port", "https://example.com:{port}/", "error.target_url_placeholder_position"},
+		{"unterminated placeholder", "https://example.com/?a={ref", "error.target_url_template_invalid"},
+		{"stray closing brace", "https://example.com/?a=ref}", "error.target_url_template_invalid"},
+		{"empty name", "https://example.com/?a={}", "error.target_url_template_invalid"},
+		{"expression name", "https://example.com/?a={ref.x}", "error.target_url_template_invalid"},
+		{"nested braces", "https://example.com/?a={{ref}}", "error.target_url_template_invalid"},
+		{"name starting with digit", "https://example.com/?a={1ref}", "error.target_url_template_invalid"},
+		{"relative url", "/path/{code}", "error.target_url_template_invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateURLTemplate(tt.url)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateURLTemplate(%q) = %v, want nil", tt.url, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ValidateURLTemplate(%q) = %v, want %s", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTargetURLWithTemplate(t *testing.T) {
+	if err := ValidateTargetURL("https://shop.example.com/track?src={ref}&code={code}"); err != nil {
+		t.Errorf("ValidateTargetURL() = %v, want nil", err)
+	}
+	if err := ValidateTargetURL("https://{ref}/track"); err == nil {
+		t.Error("ValidateTargetURL() with placeholder in host should fail")
+	}
+}
+
+func TestValidateTargetURLWithLiteralBraces(t *testing.T) {
+	// 花括号中不是合法占位符名称时按普通地址处理
+	for _, raw := range []string{
+		"https://example.com/search?q={}",
+		`https://example.com/api?filter={"status":"open"}`,
+		"https://example.com/p/{a-b}",
+	} {
+		if HasURLPlaceholders(raw) {
+			t.Errorf("HasURLPlaceholders(%q) = true, want false", raw)
+		}
+		if err := ValidateTargetURL(raw); err != nil {
+			t.Errorf("ValidateTargetURL(%q) = %v, want nil", raw, err)
+		}
+		if got := RenderURLTemplate(raw, func(string) string { return "x" }); got != raw {
+			t.Errorf("RenderURLTemplate(%q) = %q, want unchanged", raw, got)
+		}
+	}
+	if !HasURLPlaceholders("https://example.com/?q={}&code={code}") {
+		t.Error("HasURLPlaceholders() should detect {code} after a literal brace")
+	}
+}
+
+func TestRenderURLTemplateEscaping(t *testing.T) {
+	const template = "https://shop.example.com/p/{code}?src={ref}&lang={lang}#{frag}"
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   string
+	}{
+		{
+			name:   "plain values",
+			values: map[string]string{"code": "spring", "ref": "tw", "lang": "zh-CN", "frag": "top"},
+			want:   "https://shop.example.com/p/spring?src=tw&lang=zh-CN#top",
+		},
+		{
+			name:   "missing values become empty",
+			values: map[string]string{"code": "spring"},
+			want:   "https://shop.example.com/p/spring?src=&lang=#",
+		},
+		{
+			name:   "query injection is escaped",
+			values: map[string]string{"ref": "x&admin=1#frag", "code": "c"},
+			want:   "https://shop.example.com/p/c?src=x%26admin%3D1%23frag&lang=#",
+		},
+		{
+			name:   "path traversal is escaped",
+			values: map[string]string{"code": "../../admin?x=1"},
+			want:   "https://shop.example.com/p/..%2F..%2Fadmin%3Fx=1?src=&lang=#",
+		},
+		{
+			name:   "scheme and host cannot be injected",
+			values: map[string]string{"code": "//evil.example.com", "ref": "javascript:alert(1)"},
+			want:   "https://shop.example.com/p/%2F%2Fevil.example.com?src=javascript%3Aalert%281%29&lang=#",
+		},
+		{
+			name:   "CRLF is escaped",
+			values: map[string]string{"ref": "a\r\nSet-Cookie: x=1"},
+			want:   "https://shop.example.com/p/?src=a%0D%0ASet-Cookie%3A+x%3D1&lang=#",
+		},
+		{
+			name:   "placeholders in values are not expanded",
+			values: map[string]string{"ref": "{code}", "code": "c"},
+			want:   "https://shop.example.com/p/c?src=%7Bcode%7D&lang=#",
+		},
+		{
+			name:   "already encoded values are encoded again",
+			values: map[string]string{"ref": "%2F"},
+			want:   "https://shop.example.com/p/?src=%252F&lang=#",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderURLTemplate(template, func(name string) string { return tt.values[name] })
+			if got != tt.want {
+				t.Errorf("RenderURLTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderURLTemplateDotSegments(t *testing.T) {
+	const template = "https://x.example.com/a/{ref}/b?q={ref}"
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"..", "https://x.example.com/a//b?q=.."},
+		{".", "https://x.example.com/a//b?q=."},
+		{"...", "https://x.example.com/a/.../b?q=..."},
+		{"..a", "https://x.example.com/a/..a/b?q=..a"},
+		{"../..", "https://x.example.com/a/..%2F../b?q=..%2F.."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got := RenderURLTemplate(template, func(string) string { return tt.ref })
+			if got != tt.want {
+				t.Errorf("RenderURLTemplate(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+			u, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("url.Parse(%q) = %v", got, err)
+			}
+			if resolved := u.ResolveReference(&url.URL{}).Path; !strings.HasPrefix(resolved, "/a/") {
+				t.Errorf("resolved path = %q, escapes /a/", resolved)
+			}
+		})
+	}
+}
+
+func TestRenderURLTemplateLimits(t *testing.T) {
+	got := RenderURLTemplate("https://example.com/?q={q}", func(string) string { return strings.Repeat("a", 1000) })
+	if want := "https://example.com/?q=" + strings.Repeat("a", maxTemplateValueLength); got != want {
+		t.Errorf("RenderURLTemplate() length = %d, want %d", len(got), len(want))
+	}
+
+	// 非法模板原样返回，不做任何替换
+	const invalid = "https://{host}/?q={q}"
+	if got := RenderURLTemplate(invalid, func(string) string { return "x" }); got != invalid {
+		t.Errorf("RenderURLTemplate(invalid) = %q, want unchanged", got)
+	}
+
+	// 没有占位符时原样返回
+	const plain = "https://example.com/a%20b?x=1"
+	if got := RenderURLTemplate(plain, func(string) string { return "x" }); got != plain {
+		t.Errorf("RenderURLTemplate(plain) = %q, want unchanged", got)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // ValidateShortCode 校验 ShortCode 是否合法
@@ -32,18 +34,37 @@ func ValidateTargetURL(targetURL string) error {
 		return fmt.Errorf("error.target_url_required")
 	}
 
-	// 2. URL 格式校验
-	if _, err := url.ParseRequestURI(targetURL); err != nil {
+	// 2. 包含占位符时按模板校验，否则做 URL 格式校验
+	if HasURLPlaceholders(targetURL) {
+		if err := ValidateURLTemplate(targetURL); err != nil {
+			return err
+		}
+	} else if u, err := url.ParseRequestURI(targetURL); err != nil || u.Host == "" {
 		return fmt.Errorf("error.target_url_invalid")
 	}
 
-	// 3. URL 长度限制
+	// 3. 仅允许 http/https，避免 javascript: 等协议在 HTML 跳转页中执行
+	if !IsHTTPURL(targetURL) {
+		return fmt.Errorf("error.target_url_scheme_invalid")
+	}
+
+	// 4. URL 长度限制
 	if len(targetURL) > 2048 {
 		return fmt.Errorf("error.target_url_max_length")
 	}
 	return nil
 }
 
+// IsHTTPURL 判断 URL（或 URL 模板）的协议是否为 http/https；协议位于占位符之前，无需先渲染模板
+func IsHTTPURL(rawURL string) bool {
+	scheme, _, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return false
+	}
+	scheme = strings.ToLower(scheme)
+	return scheme == "http" || scheme == "https"
+}
+
 func ContainsWhitespace(s string) bool {
 	for _, r := range s {
 		if unicode.IsSpace(r) {
@@ -52,3 +73,41 @@ func ContainsWhitespace(s string) bool {
 	}
 	return false
 }
+
+// ValidateRedirectCode 校验跳转状态码是否受支持
+func ValidateRedirectCode(redirectCode int) error {
+	switch redirectCode {
+	case 301, 302, 307, 308:
+		return nil
+	default:
+		return fmt.Errorf("error.redirect_code_invalid")
+	}
+}
+
+// ValidateTagName 校验标签名是否合法
+func ValidateTagName(name string) error {
+	if name == "" {
+		return fmt.Errorf("error.tag_name_required")
+	}
+
+	if utf8.RuneCountInString(name) > 64 {
+		return fmt.Errorf("error.tag_name_too_long")
+	}
+	return nil
+}
+
+// ValidateDescription 校验说明长度（短链与标签共用）
+func ValidateDescription(description string) error {
+	if utf8.RuneCountInString(description) > 512 {
+		return fmt.Errorf("error.description_too_long")
+	}
+	return nil
+}
+
+// ValidateNotes 校验备注长度
+func ValidateNotes(notes string) error {
+	if utf8.RuneCountInString(notes) > 4096 {
+		return fmt.Errorf("error.notes_too_long")
+	}
+	return nil
+}
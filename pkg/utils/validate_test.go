@@ -0,0 +1,35 @@
+package utils
+
+import "testing"
+
+func TestValidateTargetURLScheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{"http", "http://example.com/path", ""},
+		{"https upper case scheme", "HTTPS://example.com/path", ""},
+		{"https template", "https://example.com/p/{code}", ""},
+		{"javascript", "javascript:alert(document.domain)", "error.target_url_invalid"},
+		{"javascript with slashes", "javascript://example.com/%0aalert(1)", "error.target_url_scheme_invalid"},
+		{"data", "data:text/html,<script>alert(1)</script>", "error.target_url_invalid"},
+		{"ftp", "ftp://example.com/file", "error.target_url_scheme_invalid"},
+		{"javascript template", "javascript://example.com/{code}", "error.target_url_scheme_invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTargetURL(tt.url)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateTargetURL(%q) = %v, want nil", tt.url, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ValidateTargetURL(%q) = %v, want %s", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
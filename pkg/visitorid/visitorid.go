@@ -0,0 +1,63 @@
+package visitorid
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+)
+
+// 默认截断位数：IPv4 保留 /24，IPv6 保留 /48
+const (
+	DefaultIPv4Bits = 24
+	DefaultIPv6Bits = 48
+)
+
+// TruncateIP 将 IP 截断到网段（例如 192.0.2.123 → 192.0.2.0），无法解析时返回空字符串
+func TruncateIP(ip string, v4Bits, v6Bits int) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(v4Bits, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(v6Bits, 128)).String()
+}
+
+// Hash 用 HMAC-SHA256 计算 IP 与 User-Agent 的访客标识，salt 定期轮换后无法再关联到原始 IP
+func Hash(salt []byte, ip, userAgent string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(ip))
+	mac.Write([]byte{0})
+	mac.Write([]byte(userAgent))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// NewSalt 生成随机 salt
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// NewCookieID 生成随机的第一方 Cookie 访客标识（32 位十六进制）
+func NewCookieID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// IsValidCookieID 校验 Cookie 中的访客标识格式，避免把任意内容写入统计
+func IsValidCookieID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
@@ -0,0 +1,56 @@
+package visitorid
+
+import "testing"
+
+func TestTruncateIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"192.0.2.123", "192.0.2.0"},
+		{"::ffff:192.0.2.123", "192.0.2.0"},
+		{"2001:db8:abcd:12:1:2:3:4", "2001:db8:abcd::"},
+		{"not-an-ip", ""},
+	}
+	for _, tt := range tests {
+		if got := TruncateIP(tt.ip, DefaultIPv4Bits, DefaultIPv6Bits); got != tt.want {
+			t.Errorf("TruncateIP(%q) = %q, want %q", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	salt := []byte("salt-a")
+	id := Hash(salt, "192.0.2.1", "Mozilla/5.0")
+	if len(id) != 32 {
+		t.Fatalf("Hash() length = %d, want 32", len(id))
+	}
+	if id != Hash(salt, "192.0.2.1", "Mozilla/5.0") {
+		t.Error("Hash() should be stable for the same salt and input")
+	}
+	if id == Hash([]byte("salt-b"), "192.0.2.1", "Mozilla/5.0") {
+		t.Error("Hash() should change when the salt rotates")
+	}
+	if id == Hash(salt, "192.0.2.1", "curl/8.5.0") {
+		t.Error("Hash() should depend on the user agent")
+	}
+	// 分隔符避免 IP 与 User-Agent 拼接产生歧义
+	if Hash(salt, "1.2.3.4", "5x") == Hash(salt, "1.2.3.45", "x") {
+		t.Error("Hash() should separate ip and user agent")
+	}
+}
+
+func TestCookieID(t *testing.T) {
+	id, err := NewCookieID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsValidCookieID(id) {
+		t.Errorf("IsValidCookieID(%q) = false", id)
+	}
+	for _, invalid := range []string{"", "abc", "zz" + id[2:], id + "00"} {
+		if IsValidCookieID(invalid) {
+			t.Errorf("IsValidCookieID(%q) = true", invalid)
+		}
+	}
+}
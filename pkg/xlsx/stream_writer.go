@@ -0,0 +1,177 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// 固定的包结构文件（只包含一个工作表，不含样式）
+const (
+	contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	sheetHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	sheetFooterXML = `</sheetData></worksheet>`
+)
+
+// StreamWriter 以流式方式写出只有一个工作表的 xlsx 文件，逐行写入，不在内存中保留已写出的数据
+type StreamWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	rows  int // 已写出的行数
+}
+
+// NewStreamWriter 写出包结构文件并打开工作表，sheetName 为工作表名称
+func NewStreamWriter(w io.Writer, sheetName string) (*StreamWriter, error) {
+	zw := zip.NewWriter(w)
+
+	var name bytes.Buffer
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+	workbookXML := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", workbookXML},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// 工作表必须是最后一个条目，之后的行才能直接流式写入
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err := sheet.WriteString(sheetHeaderXML); err != nil {
+		return nil, err
+	}
+
+	return &StreamWriter{zw: zw, sheet: sheet}, nil
+}
+
+// WriteRow 写入一行，数值类型写为数字单元格，其余写为内联字符串（不使用共享字符串表）
+// 行与单元格都带有 r 引用（如 A1），兼容要求显式引用的读取程序
+func (s *StreamWriter) WriteRow(cells ...interface{}) error {
+	s.rows++
+	rowRef := strconv.Itoa(s.rows)
+	if _, err := s.sheet.WriteString(`<row r="` + rowRef + `">`); err != nil {
+		return err
+	}
+
+	for i, cell := range cells {
+		ref := columnName(i) + rowRef
+		var number string
+		switch v := cell.(type) {
+		case int:
+			number = strconv.Itoa(v)
+		case int64:
+			number = strconv.FormatInt(v, 10)
+		case uint:
+			number = strconv.FormatUint(uint64(v), 10)
+		case uint64:
+			number = strconv.FormatUint(v, 10)
+		case float64:
+			number = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+
+		if number != "" {
+			if _, err := s.sheet.WriteString(`<c r="` + ref + `"><v>` + number + "</v></c>"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var text string
+		switch v := cell.(type) {
+		case string:
+			text = v
+		case bool:
+			text = strconv.FormatBool(v)
+		case nil:
+			text = ""
+		default:
+			if stringer, ok := v.(interface{ String() string }); ok {
+				text = stringer.String()
+			}
+		}
+
+		if _, err := s.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(s.sheet, []byte(text)); err != nil {
+			return err
+		}
+		if _, err := s.sheet.WriteString("</t></is></c>"); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.sheet.WriteString("</row>")
+	return err
+}
+
+// columnName 返回从 0 开始的列序号对应的列名：0 为 A，25 为 Z，26 为 AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// Flush 将缓冲区中的数据写到底层 Writer
+func (s *StreamWriter) Flush() error {
+	if err := s.sheet.Flush(); err != nil {
+		return err
+	}
+	return s.zw.Flush()
+}
+
+// Close 结束工作表并写出 zip 目录，不会关闭底层 Writer
+func (s *StreamWriter) Close() error {
+	if _, err := s.sheet.WriteString(sheetFooterXML); err != nil {
+		return err
+	}
+	if err := s.sheet.Flush(); err != nil {
+		return err
+	}
+	return s.zw.Close()
+}
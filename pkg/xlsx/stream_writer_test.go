@@ -0,0 +1,128 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// sheetXML 解析工作表时使用的结构
+type sheetXML struct {
+	Rows []struct {
+		Ref   string `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readZipFile(t *testing.T, zr *zip.Reader, name string) []byte {
+	t.Helper()
+	f, err := zr.Open(name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return data
+}
+
+func TestStreamWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	sw, err := NewStreamWriter(&buf, `links & "more"`)
+	if err != nil {
+		t.Fatalf("NewStreamWriter() error = %v", err)
+	}
+	if err := sw.WriteRow("id", "name", "count"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.WriteRow(uint(1), `<a href="x">&amp;</a>`, uint64(42), true, nil, "  two\nlines  ", 1.5, int64(-3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+
+	// 只使用内联字符串，不生成共享字符串表
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	want := []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("zip entries = %v, want %v", names, want)
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(readZipFile(t, zr, "xl/workbook.xml"), &workbook); err != nil {
+		t.Fatalf("workbook.xml is not valid XML: %v", err)
+	}
+	if len(workbook.Sheets) != 1 || workbook.Sheets[0].Name != `links & "more"` {
+		t.Errorf("sheet name = %+v, want escaped round trip", workbook.Sheets)
+	}
+
+	var sheet sheetXML
+	if err := xml.Unmarshal(readZipFile(t, zr, "xl/worksheets/sheet1.xml"), &sheet); err != nil {
+		t.Fatalf("sheet1.xml is not valid XML: %v", err)
+	}
+	if len(sheet.Rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(sheet.Rows))
+	}
+	if sheet.Rows[0].Ref != "1" || sheet.Rows[1].Ref != "2" {
+		t.Errorf("row refs = %q, %q, want 1, 2", sheet.Rows[0].Ref, sheet.Rows[1].Ref)
+	}
+
+	type cell struct{ ref, typ, value string }
+	wantCells := []cell{
+		{"A2", "", "1"},
+		{"B2", "inlineStr", `<a href="x">&amp;</a>`},
+		{"C2", "", "42"},
+		{"D2", "inlineStr", "true"},
+		{"E2", "inlineStr", ""},
+		{"F2", "inlineStr", "  two\nlines  "},
+		{"G2", "", "1.5"},
+		{"H2", "", "-3"},
+	}
+	cells := sheet.Rows[1].Cells
+	if len(cells) != len(wantCells) {
+		t.Fatalf("cells = %d, want %d", len(cells), len(wantCells))
+	}
+	for i, want := range wantCells {
+		got := cell{cells[i].Ref, cells[i].Type, cells[i].Value}
+		if cells[i].Type == "inlineStr" {
+			got.value = cells[i].Inline
+		}
+		if got != want {
+			t.Errorf("cell %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
@@ -20,6 +20,9 @@ type PageResponse[T any] struct {
 	TotalPage int `json:"totalPage"`
 	Total     int `json:"total"`
 	List      []T `json:"list"`
+
+	// NextCursor 游标分页时下一页的游标，为空表示没有更多数据（游标分页不统计 Total/TotalPage）
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // OK 构造一个成功的响应
@@ -32,6 +35,16 @@ func OK[T any](data T, message string) *Response[T] {
 	}
 }
 
+// Fail 构造一个携带数据的失败响应（如导入报告）
+func Fail[T any](data T, message string) *Response[T] {
+	return &Response[T]{
+		Success:   false,
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now().UnixMilli(),
+	}
+}
+
 // Error 构造一个失败的响应
 func Error(message string) *Response[any] {
 	return &Response[any]{